@@ -6,23 +6,18 @@ import (
 	"go/ast"
 	"go/parser"
 	"go/token"
+	"go/types"
 	"io/fs"
-	"io/ioutil"
-	"log"
 	"os"
+	"path/filepath"
+	"sort"
 	"strings"
-	"sync"
-)
-
-const (
-	breakLine = 1199
-	breakFile = "sample/echo/binder_test.go"
 )
 
 type Import struct {
-	Name  string
-	Alias string
-	Path  string
+	Name  string `json:"name"`
+	Alias string `json:"alias,omitempty"`
+	Path  string `json:"path"`
 }
 
 func (i Import) Caller() string {
@@ -33,18 +28,23 @@ func (i Import) Caller() string {
 	return i.Name
 }
 
+// SourceCode is position of a declaration. Text returns its code, instead of Data and File which are removed.
 type SourceCode struct {
 	Test, Pos token.Pos
 	End       token.Pos
-	Data      string
-	File      *os.File
+
+	source *sourceFile
 }
 
 type FilterFunc func(info fs.FileInfo) bool
 
 type Structure struct {
 	PkgName    string
+	ImportPath string
+	File       string
+	Pos        token.Pos
 	Name       string
+	TypeParams Parameters
 	Parameters Parameters
 	methods    []*FunctionStatement
 }
@@ -61,6 +61,15 @@ func (s Structure) Mermaid() (mStr string) {
 	return "class " + s.Name + "\n" + strings.Join(mStrs, "\n")
 }
 
+func (s Structure) Identifier() string {
+	pkg := s.PkgName
+	if s.ImportPath != "" {
+		pkg = s.ImportPath
+	}
+
+	return pkg + "." + s.Name
+}
+
 func (s Structure) Methods() []*FunctionStatement {
 	return s.methods
 }
@@ -68,13 +77,30 @@ func (s Structure) Methods() []*FunctionStatement {
 type Parser struct {
 	fset            *token.FileSet
 	path            string
-	functionsByName map[string]*FunctionStatement // TODO: what if 2 packages has same name? like context.
+	functionsByName map[string]*FunctionStatement // key is FunctionStatement.Identifier(), which starts with import path
 	functionCalls   []FunctionCall
-	importTable     map[string]Import
+	importTable     map[string]map[string]Import // file name -> caller -> import
 	filter          FilterFunc
 	structureTypes  map[string]Structure
+	interfaceTypes  map[string]Interface
+	implementations []Implementation
+	dynamicDispatch bool
+	typed           bool
+	syntax          map[string][]*ast.File // package identity -> files, kept to type-check in typed mode
+	typesInfo       *types.Info
+	typesPackages   map[string]*types.Package
+	importer        types.Importer          // loads interfaces out of parsed packages
+	symbolTables    map[string]*SymbolTable // package identity -> package scope
+	sources         map[string]*sourceFile  // file name -> where content of the file comes from
 	mode            parser.Mode
-	inspector       func(ctx context.Context, p *Parser, path string, pkgName string) (fch chan *FunctionStatement, f func(node ast.Node) bool)
+	recursive       bool
+	parallelism     int
+	lenient         bool
+	errors          []FileError
+	diagnostics     Diagnostics
+	modulePath      string
+	progress        *progressReporter
+	inspector       func(ctx context.Context, p *Parser, path, pkgName, importPath string) (f func(node ast.Node) bool)
 }
 
 func NewParser(path string) (p Parser) {
@@ -83,11 +109,16 @@ func NewParser(path string) (p Parser) {
 		path:            path,
 		functionsByName: make(map[string]*FunctionStatement),
 		functionCalls:   make([]FunctionCall, 0),
-		importTable:     make(map[string]Import),
+		importTable:     make(map[string]map[string]Import),
 		filter: func(info fs.FileInfo) bool {
 			return true
 		},
 		structureTypes: make(map[string]Structure),
+		interfaceTypes: make(map[string]Interface),
+		symbolTables:   make(map[string]*SymbolTable),
+		sources:        make(map[string]*sourceFile),
+		syntax:         make(map[string][]*ast.File),
+		parallelism:    1,
 		inspector:      inspector,
 	}
 
@@ -110,6 +141,37 @@ func (p *Parser) SetFilter(filter FilterFunc) {
 	p.filter = filter
 }
 
+// SetLenient makes Parse keep analyzing the files that are parsed, when some files have syntax errors.
+// Errors of skipped files are collected into Errors.
+func (p *Parser) SetLenient(lenient bool) {
+	p.lenient = lenient
+}
+
+func (p Parser) parserMode() parser.Mode {
+	if p.lenient {
+		return p.mode | parser.AllErrors
+	}
+
+	return p.mode
+}
+
+// Errors returns errors of the files that are skipped in lenient mode.
+func (p Parser) Errors() []FileError {
+	return p.errors
+}
+
+// SetRecursive makes Parse read go.mod and parse every package under the module root.
+// Functions, structures and calls are then identified by their full import path.
+// When p.path is a subdirectory of the module, only results of files under it are kept,
+// and other packages of the module are parsed to resolve calls into them.
+func (p *Parser) SetRecursive(recursive bool) {
+	p.recursive = recursive
+}
+
+func (p Parser) ModulePath() string {
+	return p.modulePath
+}
+
 func (p Parser) FuncCalls() []FunctionCall {
 	return p.functionCalls
 }
@@ -123,6 +185,32 @@ func (p Parser) Structures() []Structure {
 	return ss
 }
 
+// Imports returns imports of the file, keyed by name that the file uses to refer the package.
+func (p Parser) Imports(fileName string) map[string]Import {
+	return p.importTable[fileName]
+}
+
+// SymbolTable returns package scope of the package. pkg is import path, or package name when it is parsed out of a module.
+func (p Parser) SymbolTable(pkg string) (st *SymbolTable, ok bool) {
+	st, ok = p.symbolTables[pkg]
+	return
+}
+
+func (p *Parser) packageSymbolTable(pkgName, importPath string) *SymbolTable {
+	pkg := pkgName
+	if importPath != "" {
+		pkg = importPath
+	}
+
+	if st, ok := p.symbolTables[pkg]; ok {
+		return st
+	}
+
+	st := NewSymbolTable(nil, PackageScope, nil)
+	p.symbolTables[pkg] = st
+	return st
+}
+
 func (p Parser) Function(name string) (function *FunctionStatement, ok bool) {
 	function, ok = p.functionsByName[name]
 	return
@@ -135,71 +223,296 @@ func (p Parser) Functions() (functions []*FunctionStatement) {
 	return
 }
 
-func (p *Parser) ParseFile(source string) {
-	pkgs, err := parser.ParseFile(p.fset, p.path, source, p.mode)
+// ParseFile parses source as a file of p.path. In lenient mode, syntax error is collected into Errors instead of returned.
+func (p *Parser) ParseFile(source string) error {
+	return p.ParseFileContext(context.Background(), source)
+}
 
+// ParseFileContext is ParseFile, which stops when ctx is done. It returns error of ctx then.
+func (p *Parser) ParseFileContext(ctx context.Context, source string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	pkgs, err := parser.ParseFile(p.fset, p.path, source, p.parserMode())
 	if err != nil {
-		panic(err)
+		fileErr := FileError{File: p.path, Err: err}
+		if !p.lenient {
+			return fileErr
+		}
+
+		p.errors = append(p.errors, fileErr)
+		p.reportFileError(fileErr)
+		return nil
 	}
 
-	functions := make([]*FunctionStatement, 0)
+	p.keepSource(p.fset.File(pkgs.Pos()), []byte(source))
 
-	fch, insptr := p.inspector(context.TODO(), p, p.path, pkgs.Name.Name)
+	importPath := p.lookupImportPath(filepath.Dir(p.path))
+	ast.Inspect(pkgs, p.inspector(ctx, p, p.path, pkgs.Name.Name, importPath))
 
-	go func(fch chan *FunctionStatement) {
-		ast.Inspect(pkgs, insptr)
-		close(fch)
-	}(fch)
+	if err = ctx.Err(); err != nil {
+		return err
+	}
 
-	for function := range fch {
-		functions = append(functions, function)
+	p.keepSyntax(packageOf(importPath, pkgs.Name.Name), []*ast.File{pkgs})
+	if err = p.checkTypes(ctx); err != nil {
+		return err
 	}
 
+	p.progress.report(Progress{
+		Kind:       FileDone,
+		Package:    packageOf(importPath, filepath.Dir(p.path)),
+		File:       p.path,
+		Files:      1,
+		TotalFiles: 1,
+	})
+
 	// e.Match([]string{"GET", "POST"}, "/test", server.Test)
 	// 이런식으로 함수 자체가 넘어 갔을때, functionCalls에는 집계되지 않음.
-	for index, function := range p.functionCalls {
-		identifier := function.Identifier()
+	p.link()
+	return nil
+}
 
-		if decl, ok := p.functionsByName[identifier]; ok {
-			function.FunctionDeclaration = decl
-			decl.Calls = append(decl.Calls, function)
+// Parse parses every package in p.path. It returns the first error, unless the parser is lenient.
+func (p *Parser) Parse() (err error) {
+	return p.ParseContext(context.Background())
+}
 
-			p.functionsByName[identifier] = decl
+// ParseContext is Parse, which stops when ctx is done. It returns error of ctx then,
+// and the result of the packages that are already parsed is not linked.
+func (p *Parser) ParseContext(ctx context.Context) (err error) {
+	if p.recursive {
+		return p.parseModule(ctx)
+	}
+
+	if err = p.parseUnits(ctx, []parseUnit{{dir: p.path, importPath: p.lookupImportPath(p.path)}}); err != nil {
+		return
+	}
+
+	if err = p.checkTypes(ctx); err != nil {
+		return
+	}
+
+	p.link()
+	return
+}
+
+// parseDir parses every package in dir. importPath is used as package identity when it is not empty.
+func (p *Parser) parseDir(ctx context.Context, dir, importPath string) (err error) {
+	p.progress.report(Progress{Kind: PackageStarted, Package: packageOf(importPath, dir)})
+
+	pkgs, err := p.parsePackages(ctx, dir)
+
+	if err != nil {
+		return
+	}
+
+	totalFiles := 0
+	for _, pkg := range pkgs {
+		totalFiles += len(pkg.Files)
+	}
+	files := 0
+
+	// packages and files are inspected in sorted order, so the result does not depend on map order
+	pkgNames := make([]string, 0, len(pkgs))
+	for pkgName := range pkgs {
+		pkgNames = append(pkgNames, pkgName)
+	}
+	sort.Strings(pkgNames)
+
+	for _, pkgName := range pkgNames {
+		pkg := pkgs[pkgName]
+		pkgImportPath := importPath
+		if pkgImportPath != "" && strings.HasSuffix(pkgName, "_test") {
+			pkgImportPath += "_test"
 		}
 
-		f := p.fset.File(token.Pos(function.Pos))
-		function.File = f.Name()
-		function.LineNumber = f.Line(token.Pos(function.Pos))
+		fileNames := make([]string, 0, len(pkg.Files))
+		for fileName := range pkg.Files {
+			fileNames = append(fileNames, fileName)
+		}
+		sort.Strings(fileNames)
 
-		p.functionCalls[index] = function
+		syntax := make([]*ast.File, 0, len(fileNames))
+		for _, fileName := range fileNames {
+			syntax = append(syntax, pkg.Files[fileName])
+		}
+		p.keepSyntax(packageOf(pkgImportPath, pkgName), syntax)
+
+		insptr := p.inspector(ctx, p, dir, pkgName, pkgImportPath)
+		for _, fileName := range fileNames {
+			if ctx.Err() != nil {
+				break
+			}
+
+			ast.Inspect(pkg.Files[fileName], insptr)
+
+			files++
+			p.progress.report(Progress{
+				Kind:       FileDone,
+				Package:    packageOf(importPath, dir),
+				File:       fileName,
+				Files:      files,
+				TotalFiles: totalFiles,
+			})
+		}
+
+		if err = ctx.Err(); err != nil {
+			return
+		}
 	}
+
+	p.progress.report(Progress{
+		Kind:       PackageDone,
+		Package:    packageOf(importPath, dir),
+		Files:      files,
+		TotalFiles: totalFiles,
+	})
+
+	return
 }
 
-func (p *Parser) Parse() {
-	// TODO: recursive parse?
-	pkgs, err := parser.ParseDir(p.fset, p.path, p.filter, p.mode)
+// packageOf returns import path of the package, or fallback when it is parsed out of a module.
+func packageOf(importPath, dir string) string {
+	if importPath != "" {
+		return importPath
+	}
+
+	return dir
+}
 
+// parsePackages parses go files in dir, which passes p.filter. Unlike parser.ParseDir,
+// it keeps error of every file, and skips only the files that have error in lenient mode.
+func (p *Parser) parsePackages(ctx context.Context, dir string) (pkgs map[string]*ast.Package, err error) {
+	entries, err := os.ReadDir(dir)
 	if err != nil {
-		panic(err)
+		return
+	}
+
+	pkgs = make(map[string]*ast.Package)
+	for _, entry := range entries {
+		if err = ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".go") {
+			continue
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			return nil, err
+		}
+
+		if p.filter != nil && !p.filter(info) {
+			continue
+		}
+
+		fileName := filepath.Join(dir, entry.Name())
+		data, err := os.ReadFile(fileName)
+		if err != nil {
+			return nil, err
+		}
+
+		file, err := parser.ParseFile(p.fset, fileName, data, p.parserMode())
+		if err != nil {
+			fileErr := FileError{File: fileName, Err: err}
+			if !p.lenient {
+				return nil, fileErr
+			}
+
+			p.errors = append(p.errors, fileErr)
+			p.reportFileError(fileErr)
+			continue
+		}
+		p.keepSourceInfo(p.fset.File(file.Pos()), info.ModTime())
+
+		name := file.Name.Name
+		pkg, ok := pkgs[name]
+		if !ok {
+			pkg = &ast.Package{
+				Name:  name,
+				Files: make(map[string]*ast.File),
+			}
+			pkgs[name] = pkg
+		}
+		pkg.Files[fileName] = file
 	}
 
-	functions := make([]*FunctionStatement, 0)
+	return
+}
 
-	path := p.path
-	for pkgName, pkg := range pkgs {
-		fch, insptr := p.inspector(context.TODO(), p, path, pkgName)
+// link resolves calls, methods of structures, implementations of interfaces and dynamic calls of parsed sources.
+// ParseFile may be called several times with one parser, so every step can be run again.
+func (p *Parser) link() {
+	p.resolveTyps()
+	p.linkFunctionCalls()
+	p.linkMethods()
+	p.linkImplementations()
+	p.linkDynamicCalls()
+}
 
-		go func(fch chan *FunctionStatement) {
-			ast.Inspect(pkg, insptr)
-			close(fch)
-		}(fch)
+// resolveTyps resolves import path of named types of functions, structures and interfaces.
+// Type parameters of the declarations are left unresolved.
+func (p *Parser) resolveTyps() {
+	resolve := func(pkg string, imports map[string]Import, typeParams map[string]bool, parameters Parameters) {
+		for index, prm := range parameters {
+			if prm.Typ != nil {
+				typ := prm.Typ.resolve(pkg, imports, typeParams)
+				parameters[index].Typ = &typ
+			}
+		}
+	}
+
+	for _, f := range p.functionsByName {
+		pkg, imports, typeParams := packageOf(f.ImportPath, f.Package), p.importTable[f.Path], f.typeParamNames()
+		receiver := Parameters{f.Receiver}
+		resolve(pkg, imports, typeParams, receiver)
+		f.Receiver = receiver[0]
+		resolve(pkg, imports, typeParams, f.Parameters)
+		resolve(pkg, imports, typeParams, f.Returns)
+		resolve(pkg, imports, typeParams, f.TypeParams)
+	}
 
-		for function := range fch {
-			functions = append(functions, function)
+	for _, strct := range p.structureTypes {
+		pkg, imports, typeParams := packageOf(strct.ImportPath, strct.PkgName), p.importTable[strct.File], strct.TypeParams.typeParamNames()
+		resolve(pkg, imports, typeParams, strct.Parameters)
+		resolve(pkg, imports, typeParams, strct.TypeParams)
+	}
+
+	for _, iface := range p.interfaceTypes {
+		pkg, imports, typeParams := packageOf(iface.ImportPath, iface.PkgName), p.importTable[iface.File], iface.TypeParams.typeParamNames()
+		for _, method := range iface.Methods {
+			resolve(pkg, imports, typeParams, method.Parameters)
+			resolve(pkg, imports, typeParams, method.Returns)
 		}
+		resolve(pkg, imports, typeParams, iface.TypeParams)
 	}
+}
 
+func (p *Parser) linkFunctionCalls() {
 	for index, function := range p.functionCalls {
+		// calls of files parsed before, and possible calls are linked already
+		if function.FunctionDeclaration != nil {
+			continue
+		}
+
+		if decl, ok := p.functionsByName[function.generic]; ok && len(decl.TypeParams) != 0 {
+			function.Callee = function.generic
+		} else if function.generic != "" {
+			function.TypeArgs = nil // it is index of slice or map, not instantiation
+		}
+		function.generic = ""
+
+		// receiver is kept until it is resolved, because its type may be declared in files parsed later
+		if function.Callee == "" && function.receiver != nil {
+			if typ := genericBase(strings.TrimPrefix(function.receiver.resolve(p), "*")); typ != "" {
+				function.Callee = typ + "." + function.method
+				function.receiver = nil
+			}
+		}
+
 		identifier := function.Identifier()
 
 		if decl, ok := p.functionsByName[identifier]; ok {
@@ -217,8 +530,24 @@ func (p *Parser) Parse() {
 		p.functionCalls[index] = function
 	}
 
+	for _, st := range p.symbolTables {
+		st.resolve(p)
+	}
+}
+
+func (p *Parser) linkMethods() {
+	for id, strct := range p.structureTypes {
+		strct.methods = make([]*FunctionStatement, 0)
+		p.structureTypes[id] = strct
+	}
+
 	for _, f := range p.functionsByName {
-		id := f.Receiver.Pkg + "." + f.Receiver.Type
+		pkg := f.Receiver.Pkg
+		if f.ImportPath != "" {
+			pkg = f.ImportPath
+		}
+
+		id := pkg + "." + f.Receiver.Type
 		if strct, ok := p.structureTypes[id]; ok {
 			strct.methods = append(strct.methods, f)
 			p.structureTypes[id] = strct
@@ -226,6 +555,17 @@ func (p *Parser) Parse() {
 	}
 }
 
+// countInits returns number of init functions which are identified as id with ordinal, like id#1.
+func (p *Parser) countInits(id string) (count int) {
+	for identifier := range p.functionsByName {
+		if strings.HasPrefix(identifier, id+"#") {
+			count++
+		}
+	}
+
+	return
+}
+
 func (p *Parser) ParseImport(is *ast.ImportSpec) Import {
 	var alias string
 	if is.Name != nil {
@@ -280,12 +620,18 @@ func (p *Parser) ParseArgs(pkgName string, args []ast.Expr) (parms Parameters) {
 // TODO: p.ParseExpr( expr ast.Expr)이 필요한거 아닐까? 계속 recursive하게 호출해서 내가 원하는 타입을 리턴받을 수 있도록 (리턴도 interface로 받아서 타입 체크 해야할 듯)
 
 func (p *Parser) ParseFuncCall(pkgName string, ce *ast.CallExpr) (functionCall FunctionCall) {
-	pos := p.fset.Position(ce.Pos())
+	functionCall, _ = p.parseFuncCall(pkgName, ce)
+	return
+}
 
+// parseFuncCall is ParseFuncCall, which also returns selector of the called function when it is selected,
+// like x.Start of x.Start().
+func (p *Parser) parseFuncCall(pkgName string, ce *ast.CallExpr) (functionCall FunctionCall, selector *Selector) {
 	functionCall.Pos = int(ce.Pos())
 	functionCall.Package = pkgName
 
 	functionCall.Parameters = p.ParseArgs(pkgName, ce.Args)
+	functionCall.Chain = p.ParseChain(pkgName, ce)
 
 	switch x := ce.Fun.(type) {
 	case *ast.Ident:
@@ -293,6 +639,8 @@ func (p *Parser) ParseFuncCall(pkgName string, ce *ast.CallExpr) (functionCall F
 	case *ast.SelectorExpr: // sample/echo/response.go:87 &ast.SelectorExpr
 		s := p.ParseSelector(pkgName, x)
 		functionCall.Name = s.String()
+		functionCall.IsImportedFunction = s.ImportedSelector
+		selector = &s
 	case *ast.ParenExpr: // sample/echo/bind_test.go:280 *ast.ParenExpr
 		//log.Printf("%s:%d %#v", pos.Filename, pos.Line, x.X)
 		functionCall.Name = "(" + p.ParseType(pkgName, x.X).String() + ")"
@@ -300,60 +648,51 @@ func (p *Parser) ParseFuncCall(pkgName string, ce *ast.CallExpr) (functionCall F
 		functionCall.Name = p.ParseFuncCall(pkgName, x).String()
 		//log.Printf("%s:%d %#v %#v", pos.Filename, pos.Line, x.Fun, x.Args)
 	case *ast.ArrayType: // sample/echo/context_test.go:680 *ast.ArrayType
-		//log.Printf("%s:%d %#v %#v", pos.Filename, pos.Line, x.Elt, x.Len)
 		size := ""
 		if x.Len != nil {
-			log.Printf("%#v", x.Len)
+			size = types.ExprString(x.Len)
 		}
 
 		functionCall.Name = "[" + size + "]" + p.ParseType(pkgName, x.Elt).String()
 	case *ast.IndexExpr: // sample/echo/echo.go:961 *ast.IndexExpr
 		functionCall.Name = p.ParseArray(pkgName, x).String()
+	case *ast.IndexListExpr: // Map[int, string](xs)
+		functionCall.Name = p.ParseType(pkgName, x).String()
 	case *ast.FuncLit: // sample/echo/echo_test.go:1423 *ast.FuncLit
 		parameters, results := p.ParseFuncType(pkgName, x.Type)
 		functionCall.Name = "func" + parameters.String() + results.String()
 	case *ast.InterfaceType: // sample/echo/echo_test.go:1068 *ast.InterfaceType
-		//log.Printf("%s:%d %#v", pos.Filename, pos.Line, )
-		//functions := make([]string, 0)
-		for _, f := range x.Methods.List {
-			log.Printf("%#v %#v %#v", f.Tag, f.Type, f.Names)
-		}
-		functionCall.Name = fmt.Sprintf("interface{%#v}", x.Methods)
+		functionCall.Name = p.ParseInterface(pkgName, "", x).String()
 	default:
-		log.Printf("unknown case %s:%d (%#v)", pos.Filename, pos.Line, x)
+		p.report(Warning, x, "unsupported expression of called function")
 	}
 
-	if len(functionDeclarations) != 0 {
-		fd := functionDeclarations[len(functionDeclarations)-1]
-		//log.Println(functionCall.Name, fd)
-
-		functionCall.Parent = fd
-		//} else {
-		//	log.Println(functionCall.Name, "no function declarations")
-	}
-
-	return functionCall
+	return
 }
 
 func (p *Parser) ParseSelector(pkgName string, x *ast.SelectorExpr) (s Selector) {
-	pos := p.fset.Position(x.X.Pos())
-
 	s.Parent = x.Sel.Name
+	s.Chain = p.ParseChain(pkgName, x)
+	s.expr = x
 	switch x2 := x.X.(type) {
 	case *ast.Ident:
 		s.Field = Variable{Name: x2.Name}
 		s.ImportedSelector = x2.Obj == nil
+		// syntactic mode knows results of functions of packages which are parsed out of a module already
+		if fn, ok := p.functionsByName[x2.Name+"."+x.Sel.Name]; ok && s.ImportedSelector {
+			s.ParentType = fn.Returns.String()
+		}
 	case *ast.CallExpr:
 		s.Field = p.ParseFuncCall(pkgName, x2)
-	case *ast.SelectorExpr: // TODO: a().b().c().d.e.f() 이처럼, 여러개의 selector가 중첩되어 있을 수 있음. recursive하게 수정 필요.
+	case *ast.SelectorExpr: // a().b().c().d.e.f() 처럼 중첩된 selector는 Chain에 순서대로 기록됨
 		//log.Println(x2, x2.Pos(), x2.End(), p.fset.File(x2.Pos()).Name(), p.fset.File(x2.Pos()).Line(x2.Pos()))
 		s.Field = p.ParseSelector(pkgName, x2)
 	case *ast.TypeAssertExpr:
 		typ := p.ParseType(pkgName, x2.Type)
 		s.Field = typ
 	case *ast.UnaryExpr: // sample/echo/bind_test.go:280 *ast.UnaryExpr
-		log.Printf("%#v, %#v", x2.Op, x2.X)
-		log.Println(pos.Filename, pos.Line, s.Parent)
+		p.report(Warning, x2, "unary expression as selector operand is not analyzed")
+		s.Field = p.ParseType(pkgName, x2)
 	case *ast.IndexExpr: // sample/echo/router_test.go:2466 *ast.IndexExpr
 		//log.Printf("%#v, %#v", x2.X, x2.Index)
 		//log.Println(pos.Filename, pos.Line, s.Parent)
@@ -361,13 +700,14 @@ func (p *Parser) ParseSelector(pkgName string, x *ast.SelectorExpr) (s Selector)
 	case *ast.ParenExpr: // sample/echo/bind_test.go:280 *ast.ParenExpr
 		//log.Printf("%s:%d %#v", pos.Filename, pos.Line, x2.X)
 		s.Field = p.ParseType(pkgName, x2.X)
+	case *ast.CompositeLit: // Config{}.Load()
+		s.Field = p.ParseType(pkgName, x2)
 	default:
-		log.Printf("unknown case %s:%d (%#v)", pos.Filename, pos.Line, x2)
+		p.report(Warning, x2, "unsupported selector operand")
+		s.Field = p.ParseType(pkgName, x2)
 	}
 
-	if fc, ok := p.functionsByName[pkgName+"."+x.Sel.Name+"()"]; ok {
-		s.ParentType = fc.Returns.String()
-	}
+	p.typeSelector(&s)
 
 	return
 }
@@ -410,11 +750,13 @@ func (p *Parser) ParseType(pkgName string, x ast.Expr) (t Type) {
 		t.Name = p.ParseFuncCall(pkgName, x2).String()
 	case *ast.IndexExpr: // sample/echo/echo.go:961 *ast.IndexExpr
 		t.Name = p.ParseArray(pkgName, x2).String()
+	case *ast.IndexListExpr: // Pair[K, V]
+		_, typeArgs, _ := p.ParseTypeArgs(pkgName, x2)
+		t.Name = p.ParseType(pkgName, x2.X).String() + "[" + strings.Join(typeArgs, ", ") + "]"
 	case *ast.ArrayType: // sample/echo/context_test.go:680 *ast.ArrayType
-		//log.Printf("%s:%d %#v %#v", pos.Filename, pos.Line, x.Elt, x.Len)
 		size := ""
 		if x2.Len != nil {
-			log.Printf("%#v", x2.Len)
+			size = types.ExprString(x2.Len)
 		}
 
 		t.Name = "[" + size + "]" + p.ParseType(pkgName, x2.Elt).String()
@@ -492,19 +834,18 @@ func (p *Parser) ParseType(pkgName string, x ast.Expr) (t Type) {
 		}
 		t.Name = p.ParseType(pkgName, x2.X).String() + "[" + index + "]"
 	case *ast.InterfaceType: // sample/echo/echo_test.go:1083 *ast.InterfaceType
+		t.Name = p.ParseInterface(pkgName, "", x2).String()
 	case *ast.FuncType:
 		parameters, returns := p.ParseFuncType(pkgName, x2)
 		t.Name = "func" + parameters.String() + returns.String()
 	default:
 		// ret.Items.([]model.Subscriber)
-		//log.Printf("unknown %s:%d %#v", pos.Filename, pos.Line, x2)
+		p.report(Warning, x2, "unsupported type expression")
 	}
 
 	return
 }
 
-var functionDeclarations []*FunctionStatement
-
 func (p *Parser) ParseFuncDecl(path, pkgName string, x *ast.FuncDecl) (fs FunctionStatement) {
 	fs = FunctionStatement{
 		Package: pkgName,
@@ -522,17 +863,19 @@ func (p *Parser) ParseFuncDecl(path, pkgName string, x *ast.FuncDecl) (fs Functi
 	if x.Recv != nil {
 		receiver = p.ParseParameters(x.Recv.List[0])[0]
 		receiver.Pkg = pkgName
+		receiver.Type = genericBase(receiver.Type) // method of generic type is identified by the type, like List.Push
 
 		fs.Receiver = receiver
 	}
 
+	fs.TypeParams = p.ParseTypeParams(pkgName, x.Type.TypeParams)
 	fs.Parameters, fs.Returns = p.ParseFuncType(pkgName, x.Type)
 
 	return fs
 }
 
 func (p *Parser) ParseFuncType(pkgName string, typ *ast.FuncType) (parameters, returns Parameters) {
-	//parameters := make(Parameters, 0)
+	parameters = make(Parameters, 0)
 	if typ.Params != nil {
 		for _, parms := range typ.Params.List {
 			prms := p.ParseParameters(parms)
@@ -546,7 +889,7 @@ func (p *Parser) ParseFuncType(pkgName string, typ *ast.FuncType) (parameters, r
 		}
 	}
 
-	//returns := make(Parameters, 0)
+	returns = make(Parameters, 0)
 	if typ.Results != nil {
 		for _, r := range typ.Results.List {
 			rtrns := p.ParseParameters(r)
@@ -566,41 +909,18 @@ func (p *Parser) ParseFuncType(pkgName string, typ *ast.FuncType) (parameters, r
 func (p *Parser) ParseParameters(field *ast.Field) (parameters Parameters) {
 
 	var prm Parameter
+	typ := p.ParseTyp(field.Type)
+	prm.Typ = &typ
 
 	switch prmType := field.Type.(type) {
-	case *ast.Ident:
-		prm.Type = prmType.Name
-	case *ast.StarExpr:
+	case *ast.StarExpr: // *T, **T, *[]T
 		prm.IsPointer = true
-
-		switch xx := prmType.X.(type) {
-		case *ast.Ident:
-			prm.Type = xx.Name
-		case *ast.SelectorExpr:
-			prm.Type = xx.X.(*ast.Ident).Name + "." + xx.Sel.Name // FIXME: replace type into Typ type
-		}
-	case *ast.SelectorExpr: // use exported type for parameter type
-		prm.Type = prmType.X.(*ast.Ident).Name + "." + prmType.Sel.Name // FIXME: replace type into Typ type
-	case *ast.ArrayType: // sample/echo/context_test.go:680 *ast.ArrayType
-		//log.Printf("%s:%d %#v %#v", pos.Filename, pos.Line, x.Elt, x.Len)
-		size := ""
-		if prmType.Len != nil {
-			log.Printf("%#v", prmType.Len)
-		}
-		//log.Printf("%#v", prmType)
-		prm.Type = "[" + size + "]" + p.ParseType("", prmType.Elt).String()
-	case *ast.MapType:
-		prm.Type = "map[" + p.ParseType("", prmType.Key).String() + "]" + p.ParseType("", prmType.Value).String()
-	case *ast.ChanType:
-		if prmType.Dir == ast.RECV {
-			prm.Type = "chan<- "
-		} else if prmType.Dir == ast.SEND {
-			prm.Type = "<-chan "
-		} else {
-			prm.Type = "chan "
-		}
-
-		prm.Type += p.ParseType("", prmType.Value).String()
+		prm.Type = p.parameterType(prmType.X)
+	case *ast.Ellipsis: // args ...T
+		prm.IsVariadic = true
+		prm.Type = p.parameterType(prmType)
+	default:
+		prm.Type = p.parameterType(prmType)
 	}
 
 	if len(field.Names) == 0 {
@@ -618,85 +938,183 @@ func (p *Parser) ParseParameters(field *ast.Field) (parameters Parameters) {
 	return
 }
 
-var Nodes []ast.Node
-var mu sync.Mutex
+// parameterType returns type of parameter as written. Pointers inside of the type are kept, like *T of **T.
+func (p *Parser) parameterType(x ast.Expr) string {
+	switch prmType := x.(type) {
+	case *ast.Ident:
+		return prmType.Name
+	case *ast.SelectorExpr: // use exported type for parameter type
+		if pkg, ok := prmType.X.(*ast.Ident); ok {
+			return pkg.Name + "." + prmType.Sel.Name // package and name are kept separately in Typ
+		}
+	case *ast.ParenExpr:
+		return p.parameterType(prmType.X)
+	case *ast.StarExpr:
+		return "*" + p.parameterType(prmType.X)
+	case *ast.Ellipsis:
+		return "..." + p.parameterType(prmType.Elt)
+	case *ast.IndexExpr, *ast.IndexListExpr: // List[T], Pair[K, V]
+		return p.ParseType("", prmType).String()
+	case *ast.ArrayType: // sample/echo/context_test.go:680 *ast.ArrayType
+		size := ""
+		if prmType.Len != nil {
+			size = types.ExprString(prmType.Len)
+		}
+		return "[" + size + "]" + p.parameterType(prmType.Elt)
+	case *ast.MapType:
+		return "map[" + p.parameterType(prmType.Key) + "]" + p.parameterType(prmType.Value)
+	case *ast.ChanType:
+		switch prmType.Dir {
+		case ast.SEND:
+			return "chan<- " + p.parameterType(prmType.Value)
+		case ast.RECV:
+			return "<-chan " + p.parameterType(prmType.Value)
+		}
+		return "chan " + p.parameterType(prmType.Value)
+	case *ast.FuncType, *ast.InterfaceType, *ast.StructType: // func(int) error, interface{}, struct{Name string}
+		return p.ParseTyp(prmType).String()
+	}
 
-var currentNode ast.Node
+	if x != nil {
+		p.report(Warning, x, "unsupported parameter type")
+	}
 
-type SymbolTable struct {
-	Parent   *SymbolTable
-	symbols  map[string]string // TODO: string into type
-	Children []*SymbolTable
+	return ""
 }
 
-var symbolStack = make([]ast.Node, 0)
+// traversal is a state of inspecting a package. Every inspector has its own traversal,
+// so parsers do not share anything while they are running.
+type traversal struct {
+	stack     []ast.Node
+	functions []*FunctionStatement // function declarations that contain current node
+	imports   map[string]Import
+	scopes    *scopeBuilder
+}
 
-func stackPush(stack *[]ast.Node, value ast.Node) {
-	*stack = append(*stack, value)
+func (t *traversal) push(node ast.Node) {
+	t.stack = append(t.stack, node)
 }
 
-func stackPop(stack *[]ast.Node) (value ast.Node) {
-	value = (*stack)[len(*stack)-1]
-	*stack = (*stack)[:len(*stack)-1]
+func (t *traversal) pop() (node ast.Node) {
+	node = t.stack[len(t.stack)-1]
+	t.stack = t.stack[:len(t.stack)-1]
+
+	if len(t.functions) != 0 && node == t.functions[len(t.functions)-1].Node {
+		t.functions = t.functions[:len(t.functions)-1]
+	}
 	return
 }
 
-func inspector(ctx context.Context, p *Parser, path string, pkgName string) (fch chan *FunctionStatement, f func(node ast.Node) bool) {
-	fch = make(chan *FunctionStatement)
+// parent returns the node which contains current node.
+func (t *traversal) parent() ast.Node {
+	if len(t.stack) < 2 {
+		return nil
+	}
+
+	return t.stack[len(t.stack)-2]
+}
+
+func (t *traversal) currentFunction() *FunctionStatement {
+	if len(t.functions) == 0 {
+		return nil
+	}
 
-	Nodes = make([]ast.Node, 0)
+	return t.functions[len(t.functions)-1]
+}
+
+func inspector(ctx context.Context, p *Parser, path, pkgName, importPath string) (f func(node ast.Node) bool) {
+	t := &traversal{
+		scopes: &scopeBuilder{
+			p:          p,
+			pkgName:    pkgName,
+			importPath: importPath,
+			scope:      p.packageSymbolTable(pkgName, importPath),
+		},
+	}
 	f = func(node ast.Node) bool {
 		// golang does not allow adding method to exported type
 		if node == nil {
-			n := stackPop(&symbolStack)
-			if len(functionDeclarations) != 0 && n == functionDeclarations[len(functionDeclarations)-1].Node {
-				functionDeclarations = functionDeclarations[:len(functionDeclarations)-1]
-			}
+			t.scopes.leave(t.pop())
 			return false
 		}
-		stackPush(&symbolStack, node)
 
-		mu.Lock()
-		Nodes = append(Nodes, node)
-		mu.Unlock()
+		// stop descending when ctx is done. Inspect does not call f(nil) for the node, so it is not pushed.
+		if ctx.Err() != nil {
+			return false
+		}
 
-		//pos := p.fset.Position(node.Pos())
-		//file := p.fset.File(node.Pos())
-		//if pos.Line == breakLine && file.Name() == breakFile {
-		//	log.Printf("%s:%d %#v", file.Name(), pos.Line, node)
-		//}
+		t.push(node)
+		t.scopes.enter(node, t.parent())
 
 		switch x := node.(type) {
 		case *ast.FuncType:
 			//log.Printf("%#v", x)
-			_ = stackPop(&symbolStack)
+			_ = t.pop()
 			return false
 		case *ast.FuncDecl:
 			function := p.ParseFuncDecl(path, pkgName, x)
 			tokenFile := p.fset.File(function.SourceCode.Pos)
-			if file, err := os.Open(tokenFile.Name()); err == nil {
-				b, _ := ioutil.ReadAll(file)
-				if b != nil {
-					function.SourceCode.Data = string(b[tokenFile.Offset(x.Pos())-1 : tokenFile.Offset(x.End())])
-				}
-
-				function.Path = file.Name()
+			function.SourceCode.source = p.source(tokenFile)
+			function.Path = tokenFile.Name()
+			function.ImportPath = importPath
+			if x.Recv == nil && x.Name.Name == "init" {
+				function.Ordinal = p.countInits(function.Identifier()) + 1
 			}
 			p.functionsByName[function.Identifier()] = &function
 
-			functionDeclarations = append(functionDeclarations, &function)
+			t.functions = append(t.functions, &function)
+		case *ast.File:
+			t.imports = make(map[string]Import)
+			p.importTable[p.fset.File(x.Pos()).Name()] = t.imports
+			t.scopes.imports = t.imports
 		case *ast.ImportSpec:
 			imp := p.ParseImport(x)
-			p.importTable[imp.Caller()] = imp
+			t.imports[imp.Caller()] = imp
 		case *ast.CallExpr:
-			functionCall := p.ParseFuncCall(pkgName, x)
+			functionCall, selector := p.parseFuncCall(pkgName, x)
+			functionCall.Selector = selector
+			functionCall.expr = x
+			functionCall.Parent = t.currentFunction()
+			functionCall.ImportPath = importPath
+			functionCall.Callee = calleeIdentifier(pkgName, importPath, t.imports, t.scopes.scope, x.Fun)
+			if generic, typeArgs, ok := p.ParseTypeArgs(pkgName, x.Fun); ok {
+				functionCall.generic = calleeIdentifier(pkgName, importPath, t.imports, t.scopes.scope, generic)
+				functionCall.TypeArgs = typeArgs
+			}
+			if ident, ok := x.Fun.(*ast.Ident); ok {
+				if _, ok := t.scopes.scope.Lookup(ident.Name); ok {
+					// variable is not a member of the package, so it is not qualified with package name
+					functionCall.Name = ident.Name
+					functionCall.IsFuncValue = true
+				}
+			}
+			if sel, ok := x.Fun.(*ast.SelectorExpr); ok && functionCall.Callee == "" {
+				functionCall.receiver = t.scopes.origin(sel.X, 0)
+				functionCall.method = sel.Sel.Name
+			}
+			// keep descending, because arguments and called expression may have calls and function literals,
+			// like f(g()) or go func() {}()
 			p.functionCalls = append(p.functionCalls, functionCall)
-			_ = stackPop(&symbolStack)
-			return false
 		case *ast.TypeSpec:
 			if x2, ok := x.Type.(*ast.StructType); ok {
 				strct := p.parseStruct(pkgName, x.Name.Name, x2)
-				p.structureTypes[strct.PkgName+"."+strct.Name] = strct
+				strct.TypeParams = p.ParseTypeParams(pkgName, x.TypeParams)
+				strct.ImportPath = importPath
+				strct.File = p.fset.File(x.Pos()).Name()
+				strct.Pos = x.Pos()
+				p.structureTypes[strct.Identifier()] = strct
+			}
+
+			if x2, ok := x.Type.(*ast.InterfaceType); ok {
+				i := p.ParseInterface(pkgName, x.Name.Name, x2)
+				i.TypeParams = p.ParseTypeParams(pkgName, x.TypeParams)
+				for index, embedded := range i.Embedded {
+					i.Embedded[index] = qualifyType(t.scopes.pkg(), t.imports, embedded)
+				}
+				i.ImportPath = importPath
+				i.File = p.fset.File(x.Pos()).Name()
+				i.Pos = x.Pos()
+				p.interfaceTypes[i.Identifier()] = i
 			}
 		}
 		return true
@@ -705,7 +1123,7 @@ func inspector(ctx context.Context, p *Parser, path string, pkgName string) (fch
 	return
 }
 
-func (p Parser) parseStruct(pkgName, structName string, stct *ast.StructType) (s Structure) {
+func (p *Parser) parseStruct(pkgName, structName string, stct *ast.StructType) (s Structure) {
 	s.Parameters = make(Parameters, 0)
 	s.PkgName = pkgName
 	s.Name = structName
@@ -230,6 +230,7 @@ func Test_parseParameters(t *testing.T) {
 			wantParameters: []Parameters{{{
 				Name: "a",
 				Type: "int",
+				Typ:  named("int"),
 			}}},
 		}, {
 			name: "파라미터가 타입이 생략된 두개인 경우",
@@ -238,10 +239,12 @@ func Test_parseParameters(t *testing.T) {
 				Name:                 "a",
 				IsMultipleParameters: true,
 				Type:                 "string",
+				Typ:                  named("string"),
 			}, {
 				Name:                 "b",
 				IsMultipleParameters: false,
 				Type:                 "string",
+				Typ:                  named("string"),
 			}}},
 		}, {
 			name: "파라미터가 타입이 다른 두개인 경우",
@@ -249,9 +252,11 @@ func Test_parseParameters(t *testing.T) {
 			wantParameters: []Parameters{{{
 				Name: "a",
 				Type: "int",
+				Typ:  named("int"),
 			}}, {{
 				Name: "b",
 				Type: "string",
+				Typ:  named("string"),
 			}}},
 		}},
 		"이름이 있고, 타입이 포인터인 경우": {{
@@ -261,6 +266,7 @@ func Test_parseParameters(t *testing.T) {
 				Name:      "a",
 				IsPointer: true,
 				Type:      "string",
+				Typ:       pointer(named("string")),
 			}}},
 		}, {
 			name: "파라미터가 두개 이상인 경우",
@@ -269,10 +275,12 @@ func Test_parseParameters(t *testing.T) {
 				Name:      "a",
 				IsPointer: true,
 				Type:      "string",
+				Typ:       pointer(named("string")),
 			}}, {{
 				Name:      "b",
 				IsPointer: true,
 				Type:      "int",
+				Typ:       pointer(named("int")),
 			}}},
 		}, {
 			name: "파라미터 두개 이상이 동일한 타입을 가진 경우",
@@ -282,11 +290,13 @@ func Test_parseParameters(t *testing.T) {
 				IsMultipleParameters: true,
 				IsPointer:            true,
 				Type:                 "int",
+				Typ:                  pointer(named("int")),
 			}, {
 				Name:                 "b",
 				IsMultipleParameters: false,
 				IsPointer:            true,
 				Type:                 "int",
+				Typ:                  pointer(named("int")),
 			}}},
 		}},
 		"export된 selector인 경우": {{
@@ -295,6 +305,7 @@ func Test_parseParameters(t *testing.T) {
 			wantParameters: []Parameters{{{
 				Name: "a",
 				Type: "os.File",
+				Typ:  named("os.File"),
 			}}},
 		}, {
 			name: "파라미터가 타입이 생략된 두개인 경우",
@@ -303,10 +314,12 @@ func Test_parseParameters(t *testing.T) {
 				Name:                 "a",
 				IsMultipleParameters: true,
 				Type:                 "os.File",
+				Typ:                  named("os.File"),
 			}, {
 				Name:                 "b",
 				IsMultipleParameters: false,
 				Type:                 "os.File",
+				Typ:                  named("os.File"),
 			}}},
 		}, {
 			name: "파라미터가 타입이 다른 두개인 경우",
@@ -314,9 +327,11 @@ func Test_parseParameters(t *testing.T) {
 			wantParameters: []Parameters{{{
 				Name: "a",
 				Type: "log.Logger",
+				Typ:  named("log.Logger"),
 			}}, {{
 				Name: "b",
 				Type: "os.File",
+				Typ:  named("os.File"),
 			}}},
 		}},
 		"export된 selector이며 포인터 타입인 경우": {{
@@ -326,6 +341,7 @@ func Test_parseParameters(t *testing.T) {
 				Name:      "a",
 				IsPointer: true,
 				Type:      "os.File",
+				Typ:       pointer(named("os.File")),
 			}}},
 		}, {
 			name: "파라미터가 두개 이상인 경우",
@@ -334,10 +350,12 @@ func Test_parseParameters(t *testing.T) {
 				Name:      "a",
 				IsPointer: true,
 				Type:      "os.File",
+				Typ:       pointer(named("os.File")),
 			}}, {{
 				Name:      "b",
 				IsPointer: true,
 				Type:      "log.Logger",
+				Typ:       pointer(named("log.Logger")),
 			}}},
 		}, {
 			name: "파라미터 두개 이상이 동일한 타입을 가진 경우",
@@ -347,11 +365,13 @@ func Test_parseParameters(t *testing.T) {
 				IsMultipleParameters: true,
 				IsPointer:            true,
 				Type:                 "os.File",
+				Typ:                  pointer(named("os.File")),
 			}, {
 				Name:                 "b",
 				IsMultipleParameters: false,
 				IsPointer:            true,
 				Type:                 "os.File",
+				Typ:                  pointer(named("os.File")),
 			}}},
 		}},
 		"이름이 없는 경우": {{
@@ -360,6 +380,7 @@ func Test_parseParameters(t *testing.T) {
 			wantParameters: []Parameters{{{
 				Name: "",
 				Type: "os.File",
+				Typ:  named("os.File"),
 			}}},
 		}, {
 			name: "타입이 포인터인 경우",
@@ -368,6 +389,7 @@ func Test_parseParameters(t *testing.T) {
 				Name:      "",
 				IsPointer: true,
 				Type:      "os.File",
+				Typ:       pointer(named("os.File")),
 			}}},
 		}, {
 			name: "파라미터 두개 이상의 타입인 경우",
@@ -375,9 +397,11 @@ func Test_parseParameters(t *testing.T) {
 			wantParameters: []Parameters{{{
 				Name: "",
 				Type: "os.File",
+				Typ:  named("os.File"),
 			}}, {{
 				Name: "",
 				Type: "log.Logger",
+				Typ:  named("log.Logger"),
 			}}},
 		}, {
 			name: "파라미터 두개 이상의 타입이 포인터인 경우",
@@ -386,10 +410,158 @@ func Test_parseParameters(t *testing.T) {
 				Name:      "",
 				IsPointer: true,
 				Type:      "os.File",
+				Typ:       pointer(named("os.File")),
 			}}, {{
 				Name:      "",
 				IsPointer: true,
 				Type:      "log.Logger",
+				Typ:       pointer(named("log.Logger")),
+			}}},
+		}},
+		"가변 인자인 경우": {{
+			name: "기본 타입인 경우",
+			raw:  "(format string, args ...interface{})",
+			wantParameters: []Parameters{{{
+				Name: "format",
+				Type: "string",
+				Typ:  named("string"),
+			}}, {{
+				Name:       "args",
+				IsVariadic: true,
+				Type:       "...interface{}",
+				Typ:        &Typ{Kind: EllipsisType, Elem: &Typ{Kind: InterfaceType}},
+			}}},
+		}, {
+			name: "포인터 타입인 경우",
+			raw:  "(opts ...*http.Option)",
+			wantParameters: []Parameters{{{
+				Name:       "opts",
+				IsVariadic: true,
+				Type:       "...*http.Option",
+				Typ:        &Typ{Kind: EllipsisType, Elem: pointer(named("http.Option"))},
+			}}},
+		}},
+		"함수 타입인 경우": {{
+			name: "파라미터와 리턴이 있는 경우",
+			raw:  "(fn func(int, string) error)",
+			wantParameters: []Parameters{{{
+				Name: "fn",
+				Type: "func(int, string) error",
+				Typ: &Typ{
+					Kind:    FuncType,
+					Params:  []Typ{*named("int"), *named("string")},
+					Results: []Typ{*named("error")},
+				},
+			}}},
+		}, {
+			name: "리턴이 여러개인 경우",
+			raw:  "(fn func() (int, error))",
+			wantParameters: []Parameters{{{
+				Name: "fn",
+				Type: "func() (int, error)",
+				Typ:  &Typ{Kind: FuncType, Results: []Typ{*named("int"), *named("error")}},
+			}}},
+		}, {
+			name: "포인터 타입인 경우",
+			raw:  "(fn *func())",
+			wantParameters: []Parameters{{{
+				Name:      "fn",
+				IsPointer: true,
+				Type:      "func()",
+				Typ:       pointer(&Typ{Kind: FuncType}),
+			}}},
+		}},
+		"인터페이스 타입인 경우": {{
+			name: "빈 인터페이스인 경우",
+			raw:  "(v interface{})",
+			wantParameters: []Parameters{{{
+				Name: "v",
+				Type: "interface{}",
+				Typ:  &Typ{Kind: InterfaceType},
+			}}},
+		}, {
+			name: "메소드가 있는 인터페이스인 경우",
+			raw:  "(r interface{ io.Reader; Close() error })",
+			wantParameters: []Parameters{{{
+				Name: "r",
+				Type: "interface{io.Reader; Close() error}",
+				Typ: &Typ{Kind: InterfaceType, Fields: []TypeField{
+					{Type: *named("io.Reader")},
+					{Name: "Close", Type: Typ{Kind: FuncType, Results: []Typ{*named("error")}}},
+				}},
+			}}},
+		}},
+		"인라인 구조체인 경우": {{
+			name: "필드가 있는 구조체인 경우",
+			raw:  "(opts struct{ Name string; Size int })",
+			wantParameters: []Parameters{{{
+				Name: "opts",
+				Type: "struct{Name string; Size int}",
+				Typ: &Typ{Kind: StructType, Fields: []TypeField{
+					{Name: "Name", Type: *named("string")},
+					{Name: "Size", Type: *named("int")},
+				}},
+			}}},
+		}, {
+			name: "빈 구조체의 포인터인 경우",
+			raw:  "(done *struct{})",
+			wantParameters: []Parameters{{{
+				Name:      "done",
+				IsPointer: true,
+				Type:      "struct{}",
+				Typ:       pointer(&Typ{Kind: StructType}),
+			}}},
+		}},
+		"중첩된 포인터인 경우": {{
+			name: "이중 포인터인 경우",
+			raw:  "(a **T)",
+			wantParameters: []Parameters{{{
+				Name:      "a",
+				IsPointer: true,
+				Type:      "*T",
+				Typ:       pointer(pointer(named("T"))),
+			}}},
+		}, {
+			name: "슬라이스의 포인터인 경우",
+			raw:  "(a *[]T)",
+			wantParameters: []Parameters{{{
+				Name:      "a",
+				IsPointer: true,
+				Type:      "[]T",
+				Typ:       pointer(&Typ{Kind: SliceType, Elem: named("T")}),
+			}}},
+		}, {
+			name: "포인터의 슬라이스인 경우",
+			raw:  "(a []*os.File)",
+			wantParameters: []Parameters{{{
+				Name: "a",
+				Type: "[]*os.File",
+				Typ:  &Typ{Kind: SliceType, Elem: pointer(named("os.File"))},
+			}}},
+		}, {
+			name: "포인터 값을 가진 맵인 경우",
+			raw:  "(m map[string]*T)",
+			wantParameters: []Parameters{{{
+				Name: "m",
+				Type: "map[string]*T",
+				Typ:  &Typ{Kind: MapType, Key: named("string"), Elem: pointer(named("T"))},
+			}}},
+		}},
+		"채널인 경우": {{
+			name: "받기 전용 채널인 경우",
+			raw:  "(c <-chan int)",
+			wantParameters: []Parameters{{{
+				Name: "c",
+				Type: "<-chan int",
+				Typ:  &Typ{Kind: ChanType, Dir: ast.RECV, Elem: named("int")},
+			}}},
+		}, {
+			name: "보내기 전용 채널인 경우",
+			raw:  "(c chan<- error)",
+			wantParameters: []Parameters{{{
+				Name: "c",
+				Type: "chan<- error",
+				Typ:  &Typ{Kind: ChanType, Dir: ast.SEND, Elem: named("error")},
 			}}},
 		}}}
 
@@ -415,6 +587,19 @@ func Test_parseParameters(t *testing.T) {
 	}
 }
 
+// named returns Typ of named type, like int or os.File.
+func named(name string) *Typ {
+	if index := strings.LastIndex(name, "."); index >= 0 {
+		return &Typ{Kind: NamedType, Package: name[:index], Name: name[index+1:]}
+	}
+
+	return &Typ{Kind: NamedType, Name: name}
+}
+
+func pointer(elem *Typ) *Typ {
+	return &Typ{Kind: PointerType, Elem: elem}
+}
+
 func getParsedFuncDecl(rawCode string) *ast.FuncDecl {
 	fset := token.NewFileSet()
 
@@ -468,6 +653,7 @@ func Test_parseFuncDecl(t *testing.T) {
 				Pkg:  pkgName,
 				Name: "a",
 				Type: "int",
+				Typ:  named("int"),
 			}},
 			Returns: Parameters{},
 		},
@@ -485,6 +671,7 @@ func Test_parseFuncDecl(t *testing.T) {
 				Pkg:  pkgName,
 				Name: "a",
 				Type: "int",
+				Typ:  named("int"),
 			}},
 		},
 	}, {
@@ -502,6 +689,7 @@ func Test_parseFuncDecl(t *testing.T) {
 				Pkg:  pkgName,
 				Name: "a",
 				Type: "sampleStruct",
+				Typ:  named("sampleStruct"),
 			},
 		},
 	}, {
@@ -519,6 +707,7 @@ func Test_parseFuncDecl(t *testing.T) {
 				Pkg:  pkgName,
 				Name: "a",
 				Type: "[]sampleStruct",
+				Typ:  &Typ{Kind: SliceType, Elem: named("sampleStruct")},
 			},
 		},
 	}}
@@ -529,8 +718,9 @@ func Test_parseFuncDecl(t *testing.T) {
 			tt.want.SourceCode.Pos = tt.args.x.Pos()
 			tt.want.SourceCode.End = tt.args.x.End()
 			tt.want.Body = tt.args.x.Body
+			tt.want.Node = tt.args.x
 
-			got := p.ParseFuncDecl(tt.args.pkgName, tt.args.x)
+			got := p.ParseFuncDecl("", tt.args.pkgName, tt.args.x)
 			assert.Equal(t, got, tt.want)
 		})
 	}
@@ -648,6 +838,7 @@ func TestParseFuncCall(t *testing.T) {
 		wantFunctionCall: FunctionCall{
 			Package: pkgName,
 			Name:    pkgName + ".getA",
+			Chain:   SelectorChain{{Kind: CallSegment, Name: "getA"}},
 		},
 	}, {
 		name: "변수에 할당된 함수를 호출하는 경우",
@@ -658,6 +849,7 @@ func TestParseFuncCall(t *testing.T) {
 		wantFunctionCall: FunctionCall{
 			Package: pkgName,
 			Name:    pkgName + ".getA",
+			Chain:   SelectorChain{{Kind: CallSegment, Name: "getA"}},
 		},
 	}, {
 		name: "함수 본문이 없는 메서드를 호출하는 경우",
@@ -669,6 +861,10 @@ func TestParseFuncCall(t *testing.T) {
 			Package:            pkgName,
 			Name:               "x.getA",
 			IsImportedFunction: true,
+			Chain: SelectorChain{
+				{Kind: IdentSegment, Name: "x"},
+				{Kind: CallSegment, Name: "getA"},
+			},
 		},
 	}, {
 		name: "함수 본문이 있는 메서드를 호출하는 경우",
@@ -679,6 +875,10 @@ func TestParseFuncCall(t *testing.T) {
 		wantFunctionCall: FunctionCall{
 			Package: pkgName,
 			Name:    "x.getA",
+			Chain: SelectorChain{
+				{Kind: IdentSegment, Name: "x"},
+				{Kind: CallSegment, Name: "getA"},
+			},
 		},
 	}, {
 		name: "여러개의 메서드를 연속해서 호출하는 경우",
@@ -686,9 +886,16 @@ func TestParseFuncCall(t *testing.T) {
 			pkgName: pkgName,
 		},
 		sourceCode: `func main() {x.getA().getB()}`,
+		// Name은 호출된 함수까지의 selector 전체. 이전 기대값 "x.getA"는 테스트가 컴파일되지 않던 때의 것으로,
+		// 재귀 파싱 이전의 ParseFuncCall도 "x.getA().getB"를 반환함
 		wantFunctionCall: FunctionCall{
 			Package: pkgName,
-			Name:    "x.getA",
+			Name:    "x.getA().getB",
+			Chain: SelectorChain{
+				{Kind: IdentSegment, Name: "x"},
+				{Kind: CallSegment, Name: "getA"},
+				{Kind: CallSegment, Name: "getB"},
+			},
 		},
 	}, {
 		name: "함수에서 리턴된 메서드를 연속해서 호출하는 경우",
@@ -696,16 +903,21 @@ func TestParseFuncCall(t *testing.T) {
 			pkgName: pkgName,
 		},
 		sourceCode: `func main() {getA().getB()}`,
+		// 식별자로 호출한 함수에는 패키지 이름이 붙음. 재귀 파싱 이전의 ParseFuncCall도 같은 값을 반환함
 		wantFunctionCall: FunctionCall{
 			Package: pkgName,
-			Name:    "getA().getB",
+			Name:    pkgName + ".getA().getB",
+			Chain: SelectorChain{
+				{Kind: CallSegment, Name: "getA"},
+				{Kind: CallSegment, Name: "getB"},
+			},
 		},
 	}}
 
-	p := Parser{}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			_, fc := getParsedFunctionCall(tt.sourceCode)
+			fset, fc := getParsedFunctionCall(tt.sourceCode)
+			p := Parser{fset: fset}
 			tt.wantFunctionCall.Pos = int(fc.Pos())
 			if gotFunctionCall := p.ParseFuncCall(tt.args.pkgName, fc); !reflect.DeepEqual(gotFunctionCall, tt.wantFunctionCall) {
 				t.Errorf("ParseFuncCall() = %#v\nwant %#v", gotFunctionCall, tt.wantFunctionCall)
@@ -0,0 +1,364 @@
+package analyzer
+
+import (
+	"fmt"
+	"go/types"
+	"sort"
+	"strings"
+)
+
+// GroupBy is how nodes of call graph are grouped into subgraphs.
+type GroupBy int
+
+const (
+	NoGroup         GroupBy = iota
+	GroupByPackage          // subgraph for each package
+	GroupByReceiver         // subgraph for each receiver type. Functions without receiver are not grouped
+)
+
+// CallGraphOptions selects functions and calls of call graph.
+type CallGraphOptions struct {
+	Root         string // identifier of function to start from. Every function is drawn when it is empty
+	Depth        int    // maximum number of calls from Root. 0 means no limit
+	Group        GroupBy
+	NoStdlib     bool // hide functions of standard library, and builtin functions like len
+	NoUnresolved bool // hide functions which are not declared in parsed packages
+}
+
+// CallNode is a function of call graph. ID is identifier of the function.
+type CallNode struct {
+	ID       string
+	Package  string // import path, or package name when it is parsed out of a module
+	Receiver string
+	Name     string
+	Resolved bool // the function is declared in parsed packages
+	Stdlib   bool
+}
+
+// Label returns short name of the function, like util.Server.Run.
+func (n CallNode) Label() string {
+	var names []string
+	if n.Package != "" {
+		names = append(names, n.Package[strings.LastIndex(n.Package, "/")+1:])
+	}
+	if n.Receiver != "" {
+		names = append(names, n.Receiver)
+	}
+
+	return strings.Join(append(names, n.Name), ".")
+}
+
+// CallEdge is calls from Caller to Callee. Possible is true when every call is through interface.
+type CallEdge struct {
+	Caller   string
+	Callee   string
+	Possible bool
+}
+
+// CallGraph is functions and calls between them. Nodes and edges are sorted, so output of it is deterministic.
+type CallGraph struct {
+	Nodes []CallNode
+	Edges []CallEdge
+	Group GroupBy
+}
+
+// CallGraph returns call graph of parsed functions.
+func (p Parser) CallGraph(opts CallGraphOptions) (CallGraph, error) {
+	return p.Snapshot().CallGraph(opts)
+}
+
+// CallGraph returns call graph of the snapshot. It returns error when Root is not a function of the snapshot.
+func (s Snapshot) CallGraph(opts CallGraphOptions) (g CallGraph, err error) {
+	g.Group = opts.Group
+
+	functions := make(map[string]FunctionSnapshot, len(s.Functions))
+	packages := make(map[string]bool) // true for parsed packages, and false for imported packages
+	for _, file := range s.Imports {
+		for _, imp := range file.Imports {
+			packages[imp.Path] = false
+		}
+	}
+	for _, f := range s.Functions {
+		functions[f.Identifier] = f
+		if f.ImportPath != "" {
+			packages[f.ImportPath] = true
+		} else {
+			packages[f.Package] = true
+		}
+	}
+
+	nodes := make(map[string]CallNode)
+	node := func(id string) CallNode {
+		if n, ok := nodes[id]; ok {
+			return n
+		}
+
+		n := callNode(id, functions, packages)
+		nodes[id] = n
+		return n
+	}
+
+	hidden := func(n CallNode) bool {
+		return (opts.NoStdlib && n.Stdlib) || (opts.NoUnresolved && !n.Resolved)
+	}
+
+	// possible is true while every call between the functions is possible call
+	callees := make(map[string]map[string]bool)
+	for _, call := range s.Calls {
+		// callee of function value is the name of variable, which is not a function. function literal that is
+		// called in place has no identifier, and calls in its body are edges of the caller already
+		if call.Caller == "" || call.FuncValue || strings.HasPrefix(call.Callee, "func(") ||
+			hidden(node(call.Caller)) || hidden(node(call.Callee)) {
+			continue
+		}
+
+		if callees[call.Caller] == nil {
+			callees[call.Caller] = make(map[string]bool)
+		}
+
+		possible, ok := callees[call.Caller][call.Callee]
+		callees[call.Caller][call.Callee] = call.Possible && (possible || !ok)
+	}
+
+	included := make(map[string]bool)
+	if opts.Root == "" {
+		for id := range functions {
+			if !hidden(node(id)) {
+				included[id] = true
+			}
+		}
+		for caller, cs := range callees {
+			included[caller] = true
+			for callee := range cs {
+				included[callee] = true
+			}
+		}
+	} else {
+		if _, ok := functions[opts.Root]; !ok {
+			return g, fmt.Errorf("function %q is not found", opts.Root)
+		}
+
+		included = reachable(opts.Root, opts.Depth, callees)
+	}
+
+	for id := range included {
+		g.Nodes = append(g.Nodes, node(id))
+	}
+	sort.Slice(g.Nodes, func(i, j int) bool {
+		return g.Nodes[i].ID < g.Nodes[j].ID
+	})
+
+	for caller, cs := range callees {
+		for callee, possible := range cs {
+			if included[caller] && included[callee] {
+				g.Edges = append(g.Edges, CallEdge{Caller: caller, Callee: callee, Possible: possible})
+			}
+		}
+	}
+	sort.Slice(g.Edges, func(i, j int) bool {
+		if g.Edges[i].Caller != g.Edges[j].Caller {
+			return g.Edges[i].Caller < g.Edges[j].Caller
+		}
+
+		return g.Edges[i].Callee < g.Edges[j].Callee
+	})
+
+	return
+}
+
+// reachable returns functions which root calls within depth, in breadth first order.
+func reachable(root string, depth int, callees map[string]map[string]bool) map[string]bool {
+	depths := map[string]int{root: 0}
+	queue := []string{root}
+
+	for len(queue) != 0 {
+		current := queue[0]
+		queue = queue[1:]
+
+		if depth > 0 && depths[current] >= depth {
+			continue
+		}
+
+		for callee := range callees[current] {
+			if _, ok := depths[callee]; !ok {
+				depths[callee] = depths[current] + 1
+				queue = append(queue, callee)
+			}
+		}
+	}
+
+	included := make(map[string]bool, len(depths))
+	for id := range depths {
+		included[id] = true
+	}
+
+	return included
+}
+
+// callNode splits identifier of the function into package, receiver and name.
+func callNode(id string, functions map[string]FunctionSnapshot, packages map[string]bool) (n CallNode) {
+	n.ID = id
+
+	if f, ok := functions[id]; ok {
+		n.Package = f.Package
+		if f.ImportPath != "" {
+			n.Package = f.ImportPath
+		}
+		n.Receiver = f.Receiver.Type
+		n.Name = f.Name
+		n.Resolved = true
+		return
+	}
+
+	n.Package, n.Receiver, n.Name = splitIdentifier(id)
+	if n.Package == "" {
+		return
+	}
+
+	// standard library is imported package which path does not start with domain
+	parsed, imported := packages[n.Package]
+	if parsed {
+		_, n.Stdlib = types.Universe.Lookup(n.Name).(*types.Builtin)
+	} else if imported {
+		n.Stdlib = !strings.Contains(strings.Split(n.Package, "/")[0], ".")
+	}
+
+	return
+}
+
+// group returns identifier and label of subgraph that the node belongs to.
+func (g CallGraph) group(n CallNode) (id, label string) {
+	switch g.Group {
+	case GroupByPackage:
+		return n.Package, n.Package
+	case GroupByReceiver:
+		if n.Receiver != "" {
+			return n.Package + "." + n.Receiver, n.Receiver
+		}
+	}
+
+	return "", ""
+}
+
+// groups returns nodes of each group, and nodes without group as "".
+func (g CallGraph) groups() (ids []string, labels map[string]string, members map[string][]CallNode) {
+	labels = make(map[string]string)
+	members = make(map[string][]CallNode)
+	for _, n := range g.Nodes {
+		id, label := g.group(n)
+		if _, ok := members[id]; !ok {
+			ids = append(ids, id)
+		}
+		labels[id] = label
+		members[id] = append(members[id], n)
+	}
+	sort.Strings(ids)
+
+	return
+}
+
+// nodeIDs returns ids of nodes that mermaid can use, keyed by identifier of functions.
+func (g CallGraph) nodeIDs() map[string]string {
+	ids := make(map[string]string, len(g.Nodes))
+	used := make(map[string]bool, len(g.Nodes))
+	for _, n := range g.Nodes {
+		id := nonIDChar.ReplaceAllString(n.ID, "_")
+		for suffix := 2; used[id]; suffix++ {
+			id = fmt.Sprintf("%s_%d", nonIDChar.ReplaceAllString(n.ID, "_"), suffix)
+		}
+
+		used[id] = true
+		ids[n.ID] = id
+	}
+
+	return ids
+}
+
+// mermaidEscaper escapes text in quoted labels of mermaid, like arguments of calls which are not resolved.
+var mermaidEscaper = strings.NewReplacer(`"`, "#quot;", "<", "#lt;", ">", "#gt;")
+
+// Mermaid returns the call graph as mermaid flowchart. Functions out of parsed packages are drawn with round
+// corners, and possible calls through interface are drawn with dotted lines.
+func (g CallGraph) Mermaid() string {
+	ids := g.nodeIDs()
+	lines := []string{"flowchart LR"}
+
+	node := func(n CallNode, indent string) string {
+		if n.Resolved {
+			return indent + ids[n.ID] + "[\"" + mermaidEscaper.Replace(n.Label()) + "\"]"
+		}
+
+		return indent + ids[n.ID] + "(\"" + mermaidEscaper.Replace(n.Label()) + "\")"
+	}
+
+	groups, labels, members := g.groups()
+	for _, group := range groups {
+		if group == "" {
+			for _, n := range members[group] {
+				lines = append(lines, node(n, "    "))
+			}
+			continue
+		}
+
+		lines = append(lines, "    subgraph "+nonIDChar.ReplaceAllString("group_"+group, "_")+"[\""+mermaidEscaper.Replace(labels[group])+"\"]")
+		for _, n := range members[group] {
+			lines = append(lines, node(n, "        "))
+		}
+		lines = append(lines, "    end")
+	}
+
+	for _, e := range g.Edges {
+		arrow := " --> "
+		if e.Possible {
+			arrow = " -.-> "
+		}
+		lines = append(lines, "    "+ids[e.Caller]+arrow+ids[e.Callee])
+	}
+
+	return strings.Join(lines, "\n") + "\n"
+}
+
+// DOT returns the call graph in graphviz DOT language. Functions out of parsed packages are drawn with dashed
+// boxes, and possible calls through interface are drawn with dashed lines.
+func (g CallGraph) DOT() string {
+	lines := []string{"digraph calls {", "    rankdir=LR;", "    node [shape=box];"}
+
+	node := func(n CallNode, indent string) string {
+		attributes := "label=" + quoteDOT(n.Label())
+		if !n.Resolved {
+			attributes += ", style=dashed"
+		}
+
+		return indent + quoteDOT(n.ID) + " [" + attributes + "];"
+	}
+
+	groups, labels, members := g.groups()
+	for _, group := range groups {
+		if group == "" {
+			for _, n := range members[group] {
+				lines = append(lines, node(n, "    "))
+			}
+			continue
+		}
+
+		lines = append(lines, "    subgraph "+quoteDOT("cluster_"+group)+" {", "        label="+quoteDOT(labels[group])+";")
+		for _, n := range members[group] {
+			lines = append(lines, node(n, "        "))
+		}
+		lines = append(lines, "    }")
+	}
+
+	for _, e := range g.Edges {
+		line := "    " + quoteDOT(e.Caller) + " -> " + quoteDOT(e.Callee)
+		if e.Possible {
+			line += " [style=dashed]"
+		}
+		lines = append(lines, line+";")
+	}
+
+	return strings.Join(append(lines, "}"), "\n") + "\n"
+}
+
+func quoteDOT(s string) string {
+	return `"` + strings.ReplaceAll(strings.ReplaceAll(s, `\`, `\\`), `"`, `\"`) + `"`
+}
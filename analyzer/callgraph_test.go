@@ -0,0 +1,307 @@
+package analyzer
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func callGraphParser(t *testing.T) Parser {
+	root := writeModule(t, map[string]string{
+		"go.mod": "module example.com/sample\n",
+		"main.go": `package main
+
+import (
+	"fmt"
+
+	"example.com/sample/util"
+)
+
+func main() {
+	s := &util.Server{}
+	s.Start()
+	fmt.Println("started")
+	run(s)
+}
+
+func run(r util.Runner) {
+	r.Run()
+	n := len("run")
+	_ = n
+}
+`,
+		"util/util.go": `package util
+
+type Runner interface {
+	Run() error
+}
+
+type Server struct{}
+
+func (s *Server) Start() {
+	s.listen()
+}
+
+func (s *Server) listen() {}
+
+func (s *Server) Run() error {
+	return nil
+}
+`,
+	})
+
+	p := NewParser(root)
+	p.SetRecursive(true)
+	p.SetDynamicDispatch(true)
+	if err := p.Parse(); err != nil {
+		t.Fatal(err)
+	}
+
+	return p
+}
+
+func TestParser_CallGraph(t *testing.T) {
+	p := callGraphParser(t)
+
+	tests := []struct {
+		name      string
+		opts      CallGraphOptions
+		wantNodes []string
+		wantEdges []CallEdge
+		wantErr   bool
+	}{{
+		name: "루트 함수에서 시작하는 경우",
+		opts: CallGraphOptions{Root: "example.com/sample.main"},
+		wantNodes: []string{
+			"example.com/sample.main",
+			"example.com/sample.run",
+			"example.com/sample/util.Runner.Run",
+			"example.com/sample/util.Server.Run",
+			"example.com/sample/util.Server.Start",
+			"example.com/sample/util.Server.listen",
+			"fmt.Println",
+			"example.com/sample.len",
+		},
+		wantEdges: []CallEdge{
+			{Caller: "example.com/sample.main", Callee: "example.com/sample.run"},
+			{Caller: "example.com/sample.main", Callee: "example.com/sample/util.Server.Start"},
+			{Caller: "example.com/sample.main", Callee: "fmt.Println"},
+			{Caller: "example.com/sample.run", Callee: "example.com/sample.len"},
+			{Caller: "example.com/sample.run", Callee: "example.com/sample/util.Runner.Run"},
+			{Caller: "example.com/sample.run", Callee: "example.com/sample/util.Server.Run", Possible: true},
+			{Caller: "example.com/sample/util.Server.Start", Callee: "example.com/sample/util.Server.listen"},
+		},
+	}, {
+		name: "깊이를 제한하는 경우",
+		opts: CallGraphOptions{Root: "example.com/sample.main", Depth: 1},
+		wantNodes: []string{
+			"example.com/sample.main",
+			"example.com/sample.run",
+			"example.com/sample/util.Server.Start",
+			"fmt.Println",
+		},
+		wantEdges: []CallEdge{
+			{Caller: "example.com/sample.main", Callee: "example.com/sample.run"},
+			{Caller: "example.com/sample.main", Callee: "example.com/sample/util.Server.Start"},
+			{Caller: "example.com/sample.main", Callee: "fmt.Println"},
+		},
+	}, {
+		name: "표준 라이브러리와 해석되지 않은 함수를 제외하는 경우",
+		opts: CallGraphOptions{Root: "example.com/sample.main", NoStdlib: true, NoUnresolved: true},
+		wantNodes: []string{
+			"example.com/sample.main",
+			"example.com/sample.run",
+			"example.com/sample/util.Server.Run",
+			"example.com/sample/util.Server.Start",
+			"example.com/sample/util.Server.listen",
+		},
+		wantEdges: []CallEdge{
+			{Caller: "example.com/sample.main", Callee: "example.com/sample.run"},
+			{Caller: "example.com/sample.main", Callee: "example.com/sample/util.Server.Start"},
+			{Caller: "example.com/sample.run", Callee: "example.com/sample/util.Server.Run", Possible: true},
+			{Caller: "example.com/sample/util.Server.Start", Callee: "example.com/sample/util.Server.listen"},
+		},
+	}, {
+		name:    "루트 함수가 없는 경우",
+		opts:    CallGraphOptions{Root: "example.com/sample.unknown"},
+		wantErr: true,
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g, err := p.CallGraph(tt.opts)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+
+			nodes := make([]string, 0, len(g.Nodes))
+			for _, n := range g.Nodes {
+				nodes = append(nodes, n.ID)
+			}
+			assert.ElementsMatch(t, tt.wantNodes, nodes)
+			assert.Equal(t, tt.wantEdges, g.Edges)
+		})
+	}
+}
+
+func TestParser_CallGraphNestedCalls(t *testing.T) {
+	root := writeModule(t, map[string]string{
+		"go.mod": "module example.com/sample\n",
+		"main.go": `package main
+
+import (
+	"fmt"
+	"net/http"
+
+	"example.com/sample/util"
+)
+
+func main() {
+	fmt.Println(util.NewServer())
+	http.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		handle(r)
+	})
+	f := func() {
+		serve()
+	}
+	f()
+}
+
+func handle(r *http.Request) {}
+
+func serve() {}
+`,
+		"util/util.go": `package util
+
+type Server struct{}
+
+func NewServer() *Server {
+	return &Server{}
+}
+`,
+	})
+
+	p := NewParser(root)
+	p.SetRecursive(true)
+	assert.NoError(t, p.Parse())
+
+	g, err := p.CallGraph(CallGraphOptions{Root: "example.com/sample.main"})
+	assert.NoError(t, err)
+
+	// calls in arguments and function literals are edges from main. f is a variable, so f() is not an edge
+	assert.Equal(t, []CallEdge{
+		{Caller: "example.com/sample.main", Callee: "example.com/sample.handle"},
+		{Caller: "example.com/sample.main", Callee: "example.com/sample.serve"},
+		{Caller: "example.com/sample.main", Callee: "example.com/sample/util.NewServer"},
+		{Caller: "example.com/sample.main", Callee: "fmt.Println"},
+		{Caller: "example.com/sample.main", Callee: "net/http.HandleFunc"},
+	}, g.Edges)
+}
+
+func TestCallNode(t *testing.T) {
+	p := callGraphParser(t)
+
+	g, err := p.CallGraph(CallGraphOptions{})
+	assert.NoError(t, err)
+
+	nodes := make(map[string]CallNode)
+	for _, n := range g.Nodes {
+		nodes[n.ID] = n
+	}
+
+	assert.Equal(t, CallNode{
+		ID:       "example.com/sample/util.Server.Start",
+		Package:  "example.com/sample/util",
+		Receiver: "Server",
+		Name:     "Start",
+		Resolved: true,
+	}, nodes["example.com/sample/util.Server.Start"])
+	assert.Equal(t, "util.Server.Start", nodes["example.com/sample/util.Server.Start"].Label())
+
+	assert.True(t, nodes["fmt.Println"].Stdlib)
+	assert.True(t, nodes["example.com/sample.len"].Stdlib)
+	assert.False(t, nodes["example.com/sample/util.Runner.Run"].Stdlib)
+	assert.False(t, nodes["example.com/sample/util.Runner.Run"].Resolved)
+}
+
+func TestCallGraph_Mermaid(t *testing.T) {
+	p := callGraphParser(t)
+
+	g, err := p.CallGraph(CallGraphOptions{Root: "example.com/sample.run", Group: GroupByPackage})
+	assert.NoError(t, err)
+	assert.Equal(t, `flowchart LR
+    subgraph group_example_com_sample["example.com/sample"]
+        example_com_sample_len("sample.len")
+        example_com_sample_run["sample.run"]
+    end
+    subgraph group_example_com_sample_util["example.com/sample/util"]
+        example_com_sample_util_Runner_Run("util.Runner.Run")
+        example_com_sample_util_Server_Run["util.Server.Run"]
+    end
+    example_com_sample_run --> example_com_sample_len
+    example_com_sample_run --> example_com_sample_util_Runner_Run
+    example_com_sample_run -.-> example_com_sample_util_Server_Run
+`, g.Mermaid())
+
+	again, err := p.CallGraph(CallGraphOptions{Root: "example.com/sample.run", Group: GroupByPackage})
+	assert.NoError(t, err)
+	assert.Equal(t, g.Mermaid(), again.Mermaid())
+}
+
+func TestCallGraph_MermaidEscape(t *testing.T) {
+	root := writeModule(t, map[string]string{"go.mod": "module example.com/sample\n"})
+
+	p := NewParser(filepath.Join(root, "main.go"))
+	assert.NoError(t, p.ParseFile(`package main
+
+import "strings"
+
+func main() {
+	strings.NewReader("x").Len()
+	func() {
+		serve()
+	}()
+}
+
+func serve() {}
+`))
+
+	// label of unresolved call keeps its arguments, and function literal called in place is not a node
+	g, err := p.CallGraph(CallGraphOptions{Root: "example.com/sample.main"})
+	assert.NoError(t, err)
+	assert.Equal(t, `flowchart LR
+    example_com_sample_main["sample.main"]
+    example_com_sample_serve["sample.serve"]
+    strings_NewReader("strings.NewReader")
+    strings_NewReader__x___Len("strings.NewReader(#quot;x#quot;).Len")
+    example_com_sample_main --> example_com_sample_serve
+    example_com_sample_main --> strings_NewReader
+    example_com_sample_main --> strings_NewReader__x___Len
+`, g.Mermaid())
+}
+
+func TestCallGraph_DOT(t *testing.T) {
+	p := callGraphParser(t)
+
+	g, err := p.CallGraph(CallGraphOptions{Root: "example.com/sample/util.Server.Start", Group: GroupByReceiver})
+	assert.NoError(t, err)
+	assert.Equal(t, `digraph calls {
+    rankdir=LR;
+    node [shape=box];
+    subgraph "cluster_example.com/sample/util.Server" {
+        label="Server";
+        "example.com/sample/util.Server.Start" [label="util.Server.Start"];
+        "example.com/sample/util.Server.listen" [label="util.Server.listen"];
+    }
+    "example.com/sample/util.Server.Start" -> "example.com/sample/util.Server.listen";
+}
+`, g.DOT())
+
+	g, err = p.CallGraph(CallGraphOptions{Root: "example.com/sample.run", NoStdlib: true})
+	assert.NoError(t, err)
+	assert.Contains(t, g.DOT(), `"example.com/sample/util.Runner.Run" [label="util.Runner.Run", style=dashed];`)
+	assert.Contains(t, g.DOT(), `"example.com/sample.run" -> "example.com/sample/util.Server.Run" [style=dashed];`)
+}
@@ -0,0 +1,575 @@
+package analyzer
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/printer"
+	"go/token"
+	"go/types"
+	"strings"
+)
+
+// EdgeKind is why control flows through an edge of control-flow graph.
+type EdgeKind int
+
+const (
+	FlowEdge     EdgeKind = iota // end of block
+	TrueEdge                     // condition of if or for is true
+	FalseEdge                    // condition of if or for is false
+	CaseEdge                     // case of switch or select. Label is the case
+	BreakEdge                    // break statement
+	ContinueEdge                 // continue statement
+	GotoEdge                     // goto statement
+	ReturnEdge                   // return statement
+	PanicEdge                    // call of panic
+)
+
+var edgeKindNames = []string{"", "true", "false", "case", "break", "continue", "goto", "return", "panic"}
+
+func (k EdgeKind) String() string {
+	if int(k) < len(edgeKindNames) {
+		return edgeKindNames[k]
+	}
+
+	return "unknown"
+}
+
+// BasicBlock is a sequence of statements that run without branch. Nodes are statements, and conditions of
+// if, for, switch and select. Comment is the kind of block, like if.then or for.body.
+type BasicBlock struct {
+	Index   int
+	Comment string
+	Nodes   []ast.Node
+	Succs   []*CFGEdge
+	Preds   []*CFGEdge
+}
+
+// CFGEdge is an edge of control-flow graph.
+type CFGEdge struct {
+	From  *BasicBlock
+	To    *BasicBlock
+	Kind  EdgeKind
+	Label string
+}
+
+// CFG is control-flow graph of a function body. Blocks[0] is entry, and Exit is the last block that every
+// return and panic reaches. Each defer statement has its own defer block, which is created where the statement is.
+// Return and panic go to the block of the latest registered defer, which goes to the block of the previous one,
+// and to exit at last. Defers in if, switch, select and loops may not be registered on every path that reaches
+// return, so their blocks are defer.maybe.
+// Blocks after return, break, continue, goto or panic which no edge reaches are kept as unreachable blocks.
+type CFG struct {
+	Function string
+	Blocks   []*BasicBlock
+	Entry    *BasicBlock
+	Exit     *BasicBlock
+
+	fset *token.FileSet
+}
+
+// CFG builds control-flow graph of the function. It returns nil when the function has no body.
+func (p Parser) CFG(f *FunctionStatement) *CFG {
+	if f.Body == nil {
+		return nil
+	}
+
+	return BuildCFG(p.fset, f.Identifier(), f.Body)
+}
+
+// BuildCFG builds control-flow graph of the body. fset is used to print statements of blocks.
+func BuildCFG(fset *token.FileSet, function string, body *ast.BlockStmt) *CFG {
+	b := &cfgBuilder{
+		cfg:    &CFG{Function: function, fset: fset},
+		labels: make(map[string]*BasicBlock),
+	}
+
+	b.cfg.Entry = b.newBlock("entry")
+	b.current = b.cfg.Entry
+
+	exit := &BasicBlock{Comment: "exit"}
+	b.returns = exit
+
+	b.stmts(body.List)
+	b.jump(b.returns, FlowEdge, "")
+
+	exit.Index = len(b.cfg.Blocks)
+	b.cfg.Blocks = append(b.cfg.Blocks, exit)
+	b.cfg.Exit = exit
+
+	return b.cfg
+}
+
+// cfgTargets is where break, continue and fallthrough go in current statement.
+type cfgTargets struct {
+	outer     *cfgTargets
+	label     string
+	breaks    *BasicBlock
+	continues *BasicBlock
+	fallsTo   *BasicBlock
+}
+
+type cfgBuilder struct {
+	cfg     *CFG
+	current *BasicBlock // nil when the following statements are not reachable
+	returns *BasicBlock // where return and panic go, which is the latest defer block or exit
+	nested  int         // depth of if, switch, select and loops of the statement being built
+	targets *cfgTargets
+	labels  map[string]*BasicBlock // blocks of labeled statements, which are targets of goto
+	label   string                 // label of the statement being built
+}
+
+func (b *cfgBuilder) newBlock(comment string) *BasicBlock {
+	block := &BasicBlock{Index: len(b.cfg.Blocks), Comment: comment}
+	b.cfg.Blocks = append(b.cfg.Blocks, block)
+
+	return block
+}
+
+func (b *cfgBuilder) edge(from, to *BasicBlock, kind EdgeKind, label string) {
+	e := &CFGEdge{From: from, To: to, Kind: kind, Label: label}
+	from.Succs = append(from.Succs, e)
+	to.Preds = append(to.Preds, e)
+}
+
+// jump links current block to the target, if current block is reachable.
+func (b *cfgBuilder) jump(to *BasicBlock, kind EdgeKind, label string) {
+	if b.current != nil {
+		b.edge(b.current, to, kind, label)
+	}
+}
+
+func (b *cfgBuilder) add(node ast.Node) {
+	b.block().Nodes = append(b.current.Nodes, node)
+}
+
+// block returns current block, creating unreachable block when there is no current block.
+func (b *cfgBuilder) block() *BasicBlock {
+	if b.current == nil {
+		b.current = b.newBlock("unreachable")
+	}
+
+	return b.current
+}
+
+// labelOf returns block of the label. goto may jump forward, so the block is created by whichever comes first.
+func (b *cfgBuilder) labelOf(name string) *BasicBlock {
+	if _, ok := b.labels[name]; !ok {
+		b.labels[name] = b.newBlock("label." + name)
+	}
+
+	return b.labels[name]
+}
+
+func (b *cfgBuilder) stmts(list []ast.Stmt) {
+	for _, stmt := range list {
+		b.stmt(stmt)
+	}
+}
+
+func (b *cfgBuilder) stmt(stmt ast.Stmt) {
+	label := b.label
+	b.label = ""
+
+	switch x := stmt.(type) {
+	case nil, *ast.EmptyStmt:
+	case *ast.BlockStmt:
+		b.stmts(x.List)
+	case *ast.LabeledStmt:
+		l := b.labelOf(x.Label.Name)
+		b.jump(l, FlowEdge, "")
+		b.current = l
+
+		b.label = x.Label.Name
+		b.stmt(x.Stmt)
+	case *ast.ReturnStmt:
+		b.add(x)
+		b.jump(b.returns, ReturnEdge, "")
+		b.current = nil
+	case *ast.ExprStmt:
+		b.add(x)
+		if isPanic(x.X) {
+			b.jump(b.returns, PanicEdge, "")
+			b.current = nil
+		}
+	case *ast.BranchStmt:
+		b.branch(x)
+	case *ast.DeferStmt:
+		b.add(x)
+		b.deferStmt(x)
+	case *ast.IfStmt:
+		b.ifStmt(x)
+	case *ast.ForStmt:
+		b.forStmt(x, label)
+	case *ast.RangeStmt:
+		b.rangeStmt(x, label)
+	case *ast.SwitchStmt:
+		b.stmt(x.Init)
+		if x.Tag != nil {
+			b.add(x.Tag)
+		}
+		b.clauses(x.Body, "switch", label)
+	case *ast.TypeSwitchStmt:
+		b.stmt(x.Init)
+		b.add(x.Assign)
+		b.clauses(x.Body, "typeswitch", label)
+	case *ast.SelectStmt:
+		b.clauses(x.Body, "select", label)
+	default:
+		// assignments, declarations, go and defer statements and so on do not branch
+		b.add(x)
+	}
+}
+
+// deferStmt registers deferred call, which runs before the calls that are registered earlier.
+func (b *cfgBuilder) deferStmt(x *ast.DeferStmt) {
+	comment := "defer"
+	if b.nested != 0 {
+		comment = "defer.maybe"
+	}
+
+	block := b.newBlock(comment)
+	block.Nodes = append(block.Nodes, x.Call)
+	b.edge(block, b.returns, FlowEdge, "")
+	b.returns = block
+}
+
+func (b *cfgBuilder) branch(x *ast.BranchStmt) {
+	b.add(x)
+
+	var to *BasicBlock
+	var kind EdgeKind
+	switch x.Tok {
+	case token.BREAK:
+		kind = BreakEdge
+		for t := b.targets; t != nil && to == nil; t = t.outer {
+			if x.Label == nil || t.label == x.Label.Name {
+				to = t.breaks
+			}
+		}
+	case token.CONTINUE:
+		kind = ContinueEdge
+		for t := b.targets; t != nil && to == nil; t = t.outer {
+			if (x.Label == nil || t.label == x.Label.Name) && t.continues != nil {
+				to = t.continues
+			}
+		}
+	case token.GOTO:
+		kind = GotoEdge
+		to = b.labelOf(x.Label.Name)
+	case token.FALLTHROUGH:
+		kind = FlowEdge
+		if b.targets != nil {
+			to = b.targets.fallsTo
+		}
+	}
+
+	if to != nil {
+		b.jump(to, kind, "")
+	}
+	b.current = nil
+}
+
+func (b *cfgBuilder) ifStmt(x *ast.IfStmt) {
+	b.stmt(x.Init)
+	b.add(x.Cond)
+	cond := b.current
+
+	then := b.newBlock("if.then")
+	done := b.newBlock("if.done")
+	b.edge(cond, then, TrueEdge, "")
+
+	b.nested++
+	b.current = then
+	b.stmts(x.Body.List)
+	b.jump(done, FlowEdge, "")
+
+	if x.Else != nil {
+		els := b.newBlock("if.else")
+		b.edge(cond, els, FalseEdge, "")
+
+		b.current = els
+		b.stmt(x.Else)
+		b.jump(done, FlowEdge, "")
+	} else {
+		b.edge(cond, done, FalseEdge, "")
+	}
+	b.nested--
+
+	b.current = done
+}
+
+func (b *cfgBuilder) forStmt(x *ast.ForStmt, label string) {
+	b.stmt(x.Init)
+
+	loop := b.newBlock("for.loop")
+	body := b.newBlock("for.body")
+	done := b.newBlock("for.done")
+	b.jump(loop, FlowEdge, "")
+
+	// continue goes to post statement, or to condition when there is no post statement
+	continues := loop
+	if x.Post != nil {
+		continues = b.newBlock("for.post")
+		continues.Nodes = append(continues.Nodes, x.Post)
+		b.edge(continues, loop, FlowEdge, "")
+	}
+
+	if x.Cond != nil {
+		loop.Nodes = append(loop.Nodes, x.Cond)
+		b.edge(loop, body, TrueEdge, "")
+		b.edge(loop, done, FalseEdge, "")
+	} else {
+		b.edge(loop, body, FlowEdge, "")
+	}
+
+	b.loop(body, continues, done, label, x.Body)
+}
+
+func (b *cfgBuilder) rangeStmt(x *ast.RangeStmt, label string) {
+	b.add(x.X)
+
+	loop := b.newBlock("range.loop")
+	body := b.newBlock("range.body")
+	done := b.newBlock("range.done")
+	b.jump(loop, FlowEdge, "")
+
+	// RangeStmt is the node that assigns key and value of each iteration
+	loop.Nodes = append(loop.Nodes, x)
+	b.edge(loop, body, TrueEdge, "")
+	b.edge(loop, done, FalseEdge, "")
+
+	b.loop(body, loop, done, label, x.Body)
+}
+
+// loop builds body of for and range statements. Body goes back to continues at the end.
+func (b *cfgBuilder) loop(body, continues, done *BasicBlock, label string, stmt *ast.BlockStmt) {
+	b.targets = &cfgTargets{outer: b.targets, label: label, breaks: done, continues: continues}
+	b.nested++
+
+	b.current = body
+	b.stmts(stmt.List)
+	b.jump(continues, FlowEdge, "")
+
+	b.nested--
+	b.targets = b.targets.outer
+	b.current = done
+}
+
+// clauses builds cases of switch, type switch and select statements. Current block branches into every case,
+// and into done when there is no default case of switch.
+func (b *cfgBuilder) clauses(body *ast.BlockStmt, kind, label string) {
+	head := b.block()
+	done := b.newBlock(kind + ".done")
+
+	blocks := make([]*BasicBlock, len(body.List))
+	for index := range body.List {
+		blocks[index] = b.newBlock(kind + ".case")
+	}
+
+	hasDefault := false
+	for index, clause := range body.List {
+		caseLabel := "default"
+		var stmts []ast.Stmt
+
+		switch x := clause.(type) {
+		case *ast.CaseClause:
+			if x.List != nil {
+				caseLabel = "case " + strings.Join(exprStrings(x.List), ", ")
+			}
+			stmts = x.Body
+		case *ast.CommClause:
+			if x.Comm != nil {
+				caseLabel = "case " + b.cfg.nodeString(x.Comm)
+				blocks[index].Nodes = append(blocks[index].Nodes, x.Comm)
+			}
+			stmts = x.Body
+		}
+
+		hasDefault = hasDefault || caseLabel == "default"
+		b.edge(head, blocks[index], CaseEdge, caseLabel)
+
+		var next *BasicBlock
+		if index+1 < len(blocks) {
+			next = blocks[index+1]
+		}
+
+		b.targets = &cfgTargets{outer: b.targets, label: label, breaks: done, fallsTo: next}
+		b.nested++
+		b.current = blocks[index]
+		b.stmts(stmts)
+		b.jump(done, FlowEdge, "")
+		b.nested--
+		b.targets = b.targets.outer
+	}
+
+	// select without default waits until a case is ready
+	if !hasDefault && kind != "select" {
+		b.edge(head, done, CaseEdge, "default")
+	}
+
+	b.current = done
+}
+
+func isPanic(x ast.Expr) bool {
+	call, ok := x.(*ast.CallExpr)
+	if !ok {
+		return false
+	}
+
+	ident, ok := call.Fun.(*ast.Ident)
+	return ok && ident.Name == "panic" && ident.Obj == nil
+}
+
+// Reachable returns blocks that control reaches from entry, in order of index.
+func (c *CFG) Reachable() (blocks []*BasicBlock) {
+	reached := make([]bool, len(c.Blocks))
+	stack := []*BasicBlock{c.Entry}
+	reached[c.Entry.Index] = true
+
+	for len(stack) != 0 {
+		block := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		for _, e := range block.Succs {
+			if !reached[e.To.Index] {
+				reached[e.To.Index] = true
+				stack = append(stack, e.To)
+			}
+		}
+	}
+
+	for _, block := range c.Blocks {
+		if reached[block.Index] {
+			blocks = append(blocks, block)
+		}
+	}
+
+	return
+}
+
+// Complexity returns cyclomatic complexity of reachable blocks, which is edges - blocks + 2.
+func (c *CFG) Complexity() int {
+	blocks := c.Reachable()
+
+	edges := 0
+	for _, block := range blocks {
+		edges += len(block.Succs)
+	}
+
+	return edges - len(blocks) + 2
+}
+
+// nodeString returns the node in a line. Node of several lines is cut at the end of the first line.
+func (c *CFG) nodeString(node ast.Node) string {
+	if x, ok := node.(*ast.RangeStmt); ok {
+		// only the header of range statement is the node of loop block
+		if x.Key == nil {
+			return "range " + types.ExprString(x.X)
+		}
+
+		vars := exprStrings([]ast.Expr{x.Key})
+		if x.Value != nil {
+			vars = append(vars, types.ExprString(x.Value))
+		}
+		return strings.Join(vars, ", ") + " " + x.Tok.String() + " range " + types.ExprString(x.X)
+	}
+
+	fset := c.fset
+	if fset == nil {
+		fset = token.NewFileSet()
+	}
+
+	var buf bytes.Buffer
+	if err := printer.Fprint(&buf, fset, node); err != nil {
+		return fmt.Sprintf("%T", node)
+	}
+
+	s := buf.String()
+	if index := strings.Index(s, "\n"); index >= 0 {
+		s = strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(s[:index]), "{")) + " ..."
+	}
+
+	return s
+}
+
+// lines returns comment and statements of the block.
+func (c *CFG) lines(block *BasicBlock) []string {
+	lines := []string{fmt.Sprintf("%d: %s", block.Index, block.Comment)}
+	for _, node := range block.Nodes {
+		lines = append(lines, c.nodeString(node))
+	}
+
+	return lines
+}
+
+func (e CFGEdge) label() string {
+	if e.Label != "" {
+		return e.Label
+	}
+
+	return e.Kind.String()
+}
+
+// Mermaid returns the graph as mermaid flowchart. Entry and exit are drawn as stadiums.
+func (c *CFG) Mermaid() string {
+	graph := []string{"flowchart TD"}
+
+	for _, block := range c.Blocks {
+		lines := c.lines(block)
+		for index := range lines {
+			lines[index] = mermaidEscaper.Replace(lines[index])
+		}
+		label := strings.Join(lines, "<br/>")
+
+		if block == c.Entry || block == c.Exit {
+			graph = append(graph, fmt.Sprintf("    b%d([\"%s\"])", block.Index, label))
+		} else {
+			graph = append(graph, fmt.Sprintf("    b%d[\"%s\"]", block.Index, label))
+		}
+	}
+
+	for _, block := range c.Blocks {
+		for _, e := range block.Succs {
+			arrow := " --> "
+			if label := e.label(); label != "" {
+				arrow = " -->|\"" + mermaidEscaper.Replace(label) + "\"| "
+			}
+			graph = append(graph, fmt.Sprintf("    b%d%sb%d", e.From.Index, arrow, e.To.Index))
+		}
+	}
+
+	return strings.Join(graph, "\n") + "\n"
+}
+
+// DOT returns the graph in graphviz DOT language. Statements of blocks are aligned left.
+func (c *CFG) DOT() string {
+	lines := []string{"digraph " + quoteDOT(c.Function) + " {", "    node [shape=box];"}
+
+	for _, block := range c.Blocks {
+		// \l ends a line aligned to left, so it is appended after lines are quoted
+		label := ""
+		for _, line := range c.lines(block) {
+			quoted := quoteDOT(line)
+			label += quoted[1:len(quoted)-1] + `\l`
+		}
+
+		shape := ""
+		if block == c.Entry || block == c.Exit {
+			shape = ", style=rounded"
+		}
+		lines = append(lines, fmt.Sprintf("    b%d [label=\"%s\"%s];", block.Index, label, shape))
+	}
+
+	for _, block := range c.Blocks {
+		for _, e := range block.Succs {
+			line := fmt.Sprintf("    b%d -> b%d", e.From.Index, e.To.Index)
+			if label := e.label(); label != "" {
+				line += " [label=" + quoteDOT(label) + "]"
+			}
+			lines = append(lines, line+";")
+		}
+	}
+
+	return strings.Join(append(lines, "}"), "\n") + "\n"
+}
@@ -0,0 +1,246 @@
+package analyzer
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// buildCFG builds control-flow graph of the first function of src.
+func buildCFG(t *testing.T, src string) *CFG {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "main.go", "package main\n\n"+src, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return BuildCFG(fset, "main.f", file.Decls[0].(*ast.FuncDecl).Body)
+}
+
+// cfgEdges returns edges of the graph like "0:entry -> 1:if.then true".
+func cfgEdges(c *CFG) (edges []string) {
+	for _, block := range c.Blocks {
+		for _, e := range block.Succs {
+			edges = append(edges, fmt.Sprintf("%d:%s -> %d:%s %s", e.From.Index, e.From.Comment, e.To.Index, e.To.Comment, e.label()))
+		}
+	}
+
+	return
+}
+
+func TestBuildCFG(t *testing.T) {
+	tests := []struct {
+		name       string
+		src        string
+		edges      []string
+		complexity int
+	}{{
+		name:       "분기가 없는 함수",
+		src:        "func f() {\n\tprintln(1)\n}",
+		edges:      []string{"0:entry -> 1:exit "},
+		complexity: 1,
+	}, {
+		name: "if else",
+		src:  "func f(x int) int {\n\tif x > 0 {\n\t\tx++\n\t} else {\n\t\tx--\n\t}\n\treturn x\n}",
+		edges: []string{
+			"0:entry -> 1:if.then true",
+			"0:entry -> 3:if.else false",
+			"1:if.then -> 2:if.done ",
+			"2:if.done -> 4:exit return",
+			"3:if.else -> 2:if.done ",
+		},
+		complexity: 2,
+	}, {
+		name: "for와 break, continue",
+		src:  "func f(n int) {\n\tfor i := 0; i < n; i++ {\n\t\tif i == 1 {\n\t\t\tcontinue\n\t\t}\n\t\tbreak\n\t}\n}",
+		edges: []string{
+			"0:entry -> 1:for.loop ",
+			"1:for.loop -> 2:for.body true",
+			"1:for.loop -> 3:for.done false",
+			"2:for.body -> 5:if.then true",
+			"2:for.body -> 6:if.done false",
+			"3:for.done -> 7:exit ",
+			"4:for.post -> 1:for.loop ",
+			"5:if.then -> 4:for.post continue",
+			"6:if.done -> 3:for.done break",
+		},
+		complexity: 3,
+	}, {
+		name: "range",
+		src:  "func f(xs []int) {\n\tfor _, x := range xs {\n\t\tprintln(x)\n\t}\n}",
+		edges: []string{
+			"0:entry -> 1:range.loop ",
+			"1:range.loop -> 2:range.body true",
+			"1:range.loop -> 3:range.done false",
+			"2:range.body -> 1:range.loop ",
+			"3:range.done -> 4:exit ",
+		},
+		complexity: 2,
+	}, {
+		name: "default가 없는 switch와 fallthrough",
+		src:  "func f(x int) {\n\tswitch x {\n\tcase 1:\n\t\tfallthrough\n\tcase 2, 3:\n\t\tprintln(x)\n\t}\n}",
+		edges: []string{
+			"0:entry -> 2:switch.case case 1",
+			"0:entry -> 3:switch.case case 2, 3",
+			"0:entry -> 1:switch.done default",
+			"1:switch.done -> 4:exit ",
+			"2:switch.case -> 3:switch.case ",
+			"3:switch.case -> 1:switch.done ",
+		},
+		complexity: 3,
+	}, {
+		name: "type switch",
+		src:  "func f(x interface{}) {\n\tswitch v := x.(type) {\n\tcase int:\n\t\tprintln(v)\n\tdefault:\n\t}\n}",
+		edges: []string{
+			"0:entry -> 2:typeswitch.case case int",
+			"0:entry -> 3:typeswitch.case default",
+			"1:typeswitch.done -> 4:exit ",
+			"2:typeswitch.case -> 1:typeswitch.done ",
+			"3:typeswitch.case -> 1:typeswitch.done ",
+		},
+		complexity: 2,
+	}, {
+		name: "default가 없는 select는 case로만 진행",
+		src:  "func f(a, b chan int) {\n\tselect {\n\tcase v := <-a:\n\t\tprintln(v)\n\tcase b <- 1:\n\t}\n}",
+		edges: []string{
+			"0:entry -> 2:select.case case v := <-a",
+			"0:entry -> 3:select.case case b <- 1",
+			"1:select.done -> 4:exit ",
+			"2:select.case -> 1:select.done ",
+			"3:select.case -> 1:select.done ",
+		},
+		complexity: 2,
+	}, {
+		name: "label이 붙은 continue와 goto",
+		src:  "func f(xs [][]int) {\nouter:\n\tfor _, ys := range xs {\n\t\tfor range ys {\n\t\t\tcontinue outer\n\t\t}\n\t\tgoto end\n\t}\nend:\n\tprintln()\n}",
+		edges: []string{
+			"0:entry -> 1:label.outer ",
+			"1:label.outer -> 2:range.loop ",
+			"2:range.loop -> 3:range.body true",
+			"2:range.loop -> 4:range.done false",
+			"3:range.body -> 5:range.loop ",
+			"4:range.done -> 8:label.end ",
+			"5:range.loop -> 6:range.body true",
+			"5:range.loop -> 7:range.done false",
+			"6:range.body -> 2:range.loop continue",
+			"7:range.done -> 8:label.end goto",
+			"8:label.end -> 9:exit ",
+		},
+		complexity: 3,
+	}, {
+		name: "defer는 return과 panic 뒤, exit 전에 실행",
+		src:  "func f(ok bool) {\n\tdefer println(1)\n\tdefer println(2)\n\tif !ok {\n\t\tpanic(\"not ok\")\n\t}\n}",
+		edges: []string{
+			"0:entry -> 3:if.then true",
+			"0:entry -> 4:if.done false",
+			"1:defer -> 5:exit ",
+			"2:defer -> 1:defer ",
+			"3:if.then -> 2:defer panic",
+			"4:if.done -> 2:defer ",
+		},
+		complexity: 2,
+	}, {
+		name: "defer 전의 return은 defer를 거치지 않음",
+		src:  "func f(ok bool) {\n\tif ok {\n\t\treturn\n\t}\n\tdefer println(1)\n\tif !ok {\n\t\tdefer println(2)\n\t}\n}",
+		edges: []string{
+			"0:entry -> 1:if.then true",
+			"0:entry -> 2:if.done false",
+			"1:if.then -> 7:exit return",
+			"2:if.done -> 4:if.then true",
+			"2:if.done -> 5:if.done false",
+			"3:defer -> 7:exit ",
+			"4:if.then -> 5:if.done ",
+			"5:if.done -> 6:defer.maybe ",
+			"6:defer.maybe -> 3:defer ",
+		},
+		complexity: 3,
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := buildCFG(t, tt.src)
+
+			assert.Equal(t, tt.edges, cfgEdges(c))
+			assert.Equal(t, tt.complexity, c.Complexity())
+			assert.Equal(t, c.Blocks[0], c.Entry)
+			assert.Equal(t, c.Blocks[len(c.Blocks)-1], c.Exit)
+		})
+	}
+}
+
+func TestCFG_Defer(t *testing.T) {
+	c := buildCFG(t, "func f() {\n\tdefer println(1)\n\tdefer println(2)\n}")
+
+	assert.Equal(t, []string{"0: entry", "defer println(1)", "defer println(2)"}, c.lines(c.Blocks[0]))
+	assert.Equal(t, []string{"1: defer", "println(1)"}, c.lines(c.Blocks[1]))
+	assert.Equal(t, []string{"2: defer", "println(2)"}, c.lines(c.Blocks[2]))
+}
+
+func TestCFG_Reachable(t *testing.T) {
+	c := buildCFG(t, "func f() int {\n\treturn 1\n\tprintln(2)\n\treturn 2\n}")
+
+	if !assert.Len(t, c.Blocks, 3) {
+		return
+	}
+	assert.Equal(t, "unreachable", c.Blocks[1].Comment)
+	assert.Empty(t, c.Blocks[1].Preds)
+	assert.Equal(t, []*BasicBlock{c.Entry, c.Exit}, c.Reachable())
+	assert.Equal(t, 1, c.Complexity())
+}
+
+func TestParser_CFG(t *testing.T) {
+	root := writeModule(t, map[string]string{
+		"go.mod": "module example.com/sample\n",
+		"main.go": `package main
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
+
+func main() {
+	println(abs(-1) > 0, "abs")
+}
+`,
+	})
+
+	p := NewParser(root)
+	assert.NoError(t, p.Parse())
+
+	f, ok := p.Function("example.com/sample.abs")
+	if !assert.True(t, ok) {
+		return
+	}
+
+	c := p.CFG(f)
+	assert.Equal(t, "example.com/sample.abs", c.Function)
+	assert.Equal(t, `flowchart TD
+    b0(["0: entry<br/>x #lt; 0"])
+    b1["1: if.then<br/>return -x"]
+    b2["2: if.done<br/>return x"]
+    b3(["3: exit"])
+    b0 -->|"true"| b1
+    b0 -->|"false"| b2
+    b1 -->|"return"| b3
+    b2 -->|"return"| b3
+`, c.Mermaid())
+
+	f, ok = p.Function("example.com/sample.main")
+	if !assert.True(t, ok) {
+		return
+	}
+
+	assert.Equal(t, `digraph "example.com/sample.main" {
+    node [shape=box];
+    b0 [label="0: entry\lprintln(abs(-1) > 0, \"abs\")\l", style=rounded];
+    b1 [label="1: exit\l", style=rounded];
+    b0 -> b1;
+}
+`, p.CFG(f).DOT())
+}
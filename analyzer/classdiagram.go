@@ -0,0 +1,323 @@
+package analyzer
+
+import (
+	"go/ast"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// relation is kind of an edge of class diagram.
+type relation int
+
+const (
+	inheritance relation = iota // embedded type
+	composition                 // field of the type
+	aggregation                 // field of pointer, slice, map or channel of the type
+	realization                 // implementation of interface
+)
+
+// classEdge is an edge of class diagram. from and to are identifiers of types,
+// and from embeds, has or implements to. label is name of the field.
+type classEdge struct {
+	from     string
+	to       string
+	relation relation
+	label    string
+}
+
+// mermaid returns the edge in mermaid. Parents of inheritance and realization, and owners of composition
+// and aggregation are written on the left.
+func (e classEdge) mermaid(ids map[string]string) (line string) {
+	switch e.relation {
+	case inheritance:
+		line = ids[e.to] + " <|-- " + ids[e.from]
+	case realization:
+		line = ids[e.to] + " <|.. " + ids[e.from]
+	case composition:
+		line = ids[e.from] + " *-- " + ids[e.to]
+	case aggregation:
+		line = ids[e.from] + " o-- " + ids[e.to]
+	}
+
+	if e.label != "" {
+		line += " : " + e.label
+	}
+
+	return
+}
+
+// ClassDiagram returns mermaid class diagram of structures and interfaces of the packages.
+// pkgs are import paths, or package names when they are parsed out of a module. Every package is drawn when pkgs is empty.
+//
+// Exported members are marked with +, and unexported members with -. Embedding is drawn as inheritance,
+// fields of parsed structures as composition, or aggregation when the field refers the structure by pointer,
+// slice, map or channel, and implementations of interfaces as realization.
+func (p Parser) ClassDiagram(pkgs ...string) string {
+	drawn := func(pkgName, importPath string) bool {
+		if len(pkgs) == 0 {
+			return true
+		}
+
+		for _, pkg := range pkgs {
+			if pkg == importPath || (importPath == "" && pkg == pkgName) {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	structures := make([]Structure, 0)
+	for _, strct := range p.structureTypes {
+		if drawn(strct.PkgName, strct.ImportPath) {
+			structures = append(structures, strct)
+		}
+	}
+	sort.Slice(structures, func(i, j int) bool {
+		return structures[i].Identifier() < structures[j].Identifier()
+	})
+
+	interfaces := make([]Interface, 0)
+	for _, iface := range p.Interfaces() {
+		if drawn(iface.PkgName, iface.ImportPath) {
+			interfaces = append(interfaces, iface)
+		}
+	}
+
+	ids := p.classIDs()
+	lines := []string{"classDiagram"}
+
+	for _, strct := range structures {
+		lines = append(lines, "    class "+ids[strct.Identifier()]+" {")
+		for _, field := range strct.Parameters {
+			if field.Name == "" {
+				continue
+			}
+			lines = append(lines, "        "+visibility(field.Name)+mermaidType(field)+" "+field.Name)
+		}
+
+		methods := append([]*FunctionStatement{}, strct.Methods()...)
+		sort.Slice(methods, func(i, j int) bool {
+			return methods[i].Name < methods[j].Name
+		})
+		for _, method := range methods {
+			lines = append(lines, "        "+visibility(method.Name)+mermaidMethod(method.Name, method.Parameters, method.Returns))
+		}
+		lines = append(lines, "    }")
+	}
+
+	for _, iface := range interfaces {
+		lines = append(lines, "    class "+ids[iface.Identifier()]+" {", "        <<interface>>")
+		for _, method := range iface.Methods {
+			lines = append(lines, "        "+visibility(method.Name)+mermaidMethod(method.Name, method.Parameters, method.Returns))
+		}
+		lines = append(lines, "    }")
+	}
+
+	for _, edge := range p.classEdges(structures, interfaces) {
+		lines = append(lines, "    "+edge.mermaid(ids))
+	}
+
+	return strings.Join(lines, "\n") + "\n"
+}
+
+// classEdges returns edges from the structures and interfaces to parsed types, sorted by source, target and label.
+func (p Parser) classEdges(structures []Structure, interfaces []Interface) (edges []classEdge) {
+	for _, strct := range structures {
+		pkg := strct.PkgName
+		if strct.ImportPath != "" {
+			pkg = strct.ImportPath
+		}
+		imports := p.importTable[strct.File]
+
+		for _, field := range strct.Parameters {
+			typ, rel, ok := fieldRelation(field)
+			if !ok {
+				continue
+			}
+
+			to := qualifyType(pkg, imports, typ)
+			if !p.isClass(to) {
+				continue
+			}
+
+			edges = append(edges, classEdge{from: strct.Identifier(), to: to, relation: rel, label: field.Name})
+		}
+
+		for _, implementation := range p.Implements("*" + strct.Identifier()) {
+			edges = append(edges, classEdge{from: strct.Identifier(), to: implementation.Interface, relation: realization})
+		}
+	}
+
+	for _, iface := range interfaces {
+		for _, embedded := range iface.Embedded {
+			if p.isClass(embedded) {
+				edges = append(edges, classEdge{from: iface.Identifier(), to: embedded, relation: inheritance})
+			}
+		}
+	}
+
+	sort.SliceStable(edges, func(i, j int) bool {
+		if edges[i].from != edges[j].from {
+			return edges[i].from < edges[j].from
+		}
+		if edges[i].to != edges[j].to {
+			return edges[i].to < edges[j].to
+		}
+
+		return edges[i].label < edges[j].label
+	})
+
+	return
+}
+
+// fieldRelation returns name of the type that the field refers, and how it refers the type.
+func fieldRelation(field Parameter) (typ string, rel relation, ok bool) {
+	if field.Typ == nil {
+		return
+	}
+
+	t := *field.Typ
+	switch {
+	case field.Name == "":
+		t = t.Deref()
+		rel = inheritance
+	case t.Kind == NamedType || t.Kind == InstanceType:
+		rel = composition
+	default:
+		rel = aggregation
+		for t.Elem != nil && t.Kind != InstanceType {
+			t = *t.Elem
+		}
+	}
+
+	if t.Kind == InstanceType {
+		t = *t.Elem
+	}
+	if t.Kind != NamedType {
+		return
+	}
+
+	return t.String(), rel, true
+}
+
+func (p Parser) isClass(id string) bool {
+	if _, ok := p.structureTypes[id]; ok {
+		return true
+	}
+
+	_, ok := p.interfaceTypes[id]
+	return ok
+}
+
+// nonIDChar is characters that can not be used in ids of mermaid.
+var nonIDChar = regexp.MustCompile(`[^A-Za-z0-9_]`)
+
+// classIDs returns names of classes, keyed by identifier of types. Name of the type is used when it is unique,
+// and package is prepended when types of different packages have the same name.
+func (p Parser) classIDs() map[string]string {
+	type class struct {
+		id, pkgName, name string
+	}
+
+	classes := make([]class, 0, len(p.structureTypes)+len(p.interfaceTypes))
+	for id, strct := range p.structureTypes {
+		classes = append(classes, class{id: id, pkgName: strct.PkgName, name: strct.Name})
+	}
+	for id, iface := range p.interfaceTypes {
+		classes = append(classes, class{id: id, pkgName: iface.PkgName, name: iface.Name})
+	}
+
+	names := make(map[string]int)
+	qualified := make(map[string]int)
+	for _, c := range classes {
+		names[c.name]++
+		qualified[c.pkgName+"_"+c.name]++
+	}
+
+	ids := make(map[string]string, len(classes))
+	for _, c := range classes {
+		switch {
+		case names[c.name] == 1:
+			ids[c.id] = c.name
+		case qualified[c.pkgName+"_"+c.name] == 1:
+			ids[c.id] = c.pkgName + "_" + c.name
+		default:
+			ids[c.id] = nonIDChar.ReplaceAllString(c.id, "_")
+		}
+	}
+
+	return ids
+}
+
+func visibility(name string) string {
+	if ast.IsExported(name) {
+		return "+"
+	}
+
+	return "-"
+}
+
+func mermaidMethod(name string, parameters, returns Parameters) string {
+	prms := make([]string, 0, len(parameters))
+	for _, prm := range parameters {
+		prms = append(prms, strings.TrimSpace(prm.Name+" "+mermaidType(prm)))
+	}
+
+	rtrns := make([]string, 0, len(returns))
+	for _, rtrn := range returns {
+		rtrns = append(rtrns, mermaidType(rtrn))
+	}
+
+	method := name + "(" + strings.Join(prms, ", ") + ")"
+	if len(rtrns) != 0 {
+		method += " " + strings.Join(rtrns, ", ")
+	}
+
+	return method
+}
+
+// mermaidType returns type of the parameter that mermaid can draw. Generic types use ~ instead of brackets,
+// and types with parentheses or braces are shortened to their kind, like func.
+func mermaidType(prm Parameter) string {
+	if prm.Typ == nil {
+		return prm.Type
+	}
+
+	return mermaidTyp(*prm.Typ)
+}
+
+func mermaidTyp(t Typ) string {
+	switch t.Kind {
+	case PointerType:
+		return "*" + mermaidTyp(*t.Elem)
+	case SliceType:
+		return "[]" + mermaidTyp(*t.Elem)
+	case ArrayType:
+		return "[" + t.Len + "]" + mermaidTyp(*t.Elem)
+	case EllipsisType:
+		return "..." + mermaidTyp(*t.Elem)
+	case MapType:
+		return "map[" + mermaidTyp(*t.Key) + "]" + mermaidTyp(*t.Elem)
+	case ChanType:
+		return "chan " + mermaidTyp(*t.Elem)
+	case FuncType:
+		return "func"
+	case StructType:
+		return "struct"
+	case InterfaceType:
+		if len(t.Fields) == 0 {
+			return "any"
+		}
+		return "interface"
+	case InstanceType:
+		args := make([]string, 0, len(t.TypeArgs))
+		for _, arg := range t.TypeArgs {
+			args = append(args, mermaidTyp(arg))
+		}
+		return mermaidTyp(*t.Elem) + "~" + strings.Join(args, ", ") + "~"
+	}
+
+	return t.String()
+}
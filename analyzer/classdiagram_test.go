@@ -0,0 +1,125 @@
+package analyzer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParser_ClassDiagram(t *testing.T) {
+	root := writeModule(t, map[string]string{
+		"go.mod": "module example.com/sample\n",
+		"main.go": `package main
+
+import "example.com/sample/store"
+
+type Base struct {
+	id int
+}
+
+func (b Base) ID() int {
+	return b.id
+}
+
+type Server struct {
+	Base
+	Name     string
+	store    store.Store
+	handlers map[string]*Handler
+	log      func(string)
+}
+
+func (s *Server) Close() error {
+	return nil
+}
+
+func (s *Server) serve(h Handler, opts ...string) (int, error) {
+	return 0, nil
+}
+
+type Handler struct{}
+
+type Closer interface {
+	Close() error
+}
+
+type Service interface {
+	Closer
+	ID() int
+}
+`,
+		"store/store.go": `package store
+
+type Store struct {
+	Items []Item[string]
+}
+
+type Item[T any] struct {
+	Value T
+}
+`,
+	})
+
+	p := NewParser(root)
+	p.SetRecursive(true)
+	assert.NoError(t, p.Parse())
+
+	diagram := p.ClassDiagram("example.com/sample")
+	assert.Equal(t, `classDiagram
+    class Base {
+        -int id
+        +ID() int
+    }
+    class Handler {
+    }
+    class Server {
+        +string Name
+        -store.Store store
+        -map[string]*Handler handlers
+        -func log
+        +Close() error
+        -serve(h Handler, opts ...string) int, error
+    }
+    class Closer {
+        <<interface>>
+        +Close() error
+    }
+    class Service {
+        <<interface>>
+        +ID() int
+    }
+    Base <|-- Server
+    Closer <|.. Server
+    Server o-- Handler : handlers
+    Service <|.. Server
+    Server *-- Store : store
+    Closer <|-- Service
+`, diagram)
+
+	tests := []struct {
+		name     string
+		pkgs     []string
+		contains []string
+		excludes []string
+	}{{
+		name:     "다른 패키지의 구조체를 그리는 경우",
+		pkgs:     []string{"example.com/sample/store"},
+		contains: []string{"class Store {", "+[]Item~string~ Items", "+T Value", "Store o-- Item : Items"},
+		excludes: []string{"class Server"},
+	}, {
+		name:     "모든 패키지를 그리는 경우",
+		contains: []string{"class Server {", "class Store {", "Server *-- Store : store"},
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diagram := p.ClassDiagram(tt.pkgs...)
+			for _, s := range tt.contains {
+				assert.Contains(t, diagram, s)
+			}
+			for _, s := range tt.excludes {
+				assert.NotContains(t, diagram, s)
+			}
+		})
+	}
+}
@@ -0,0 +1,154 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"go/token"
+	"io"
+	"strings"
+)
+
+type Severity int
+
+const (
+	Info Severity = iota + 1
+	Warning
+	Error
+)
+
+func (s Severity) String() string {
+	switch s {
+	case Info:
+		return "info"
+	case Warning:
+		return "warning"
+	case Error:
+		return "error"
+	}
+
+	return "unknown"
+}
+
+func (s Severity) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+func (s *Severity) UnmarshalText(text []byte) error {
+	for _, severity := range []Severity{Info, Warning, Error} {
+		if severity.String() == string(text) {
+			*s = severity
+			return nil
+		}
+	}
+
+	return fmt.Errorf("unknown severity %q", text)
+}
+
+// Diagnostic is a report about source code that the analyzer can not handle completely.
+// NodeKind is go type of the ast node like "*ast.UnaryExpr", and it is empty for syntax error.
+type Diagnostic struct {
+	Severity Severity       `json:"severity"`
+	Position token.Position `json:"position"`
+	NodeKind string         `json:"node_kind,omitempty"`
+	Message  string         `json:"message"`
+}
+
+func (d Diagnostic) String() string {
+	s := fmt.Sprintf("%s: %s: %s", d.Position, d.Severity, d.Message)
+	if d.NodeKind != "" {
+		s += " (" + d.NodeKind + ")"
+	}
+
+	return s
+}
+
+type Diagnostics []Diagnostic
+
+// Filter returns diagnostics that f returns true.
+func (ds Diagnostics) Filter(f func(d Diagnostic) bool) (filtered Diagnostics) {
+	for _, d := range ds {
+		if f(d) {
+			filtered = append(filtered, d)
+		}
+	}
+
+	return
+}
+
+// AtLeast returns diagnostics which are severe than or equal to severity.
+func (ds Diagnostics) AtLeast(severity Severity) Diagnostics {
+	return ds.Filter(func(d Diagnostic) bool {
+		return d.Severity >= severity
+	})
+}
+
+// CountByNodeKind counts diagnostics of each node kind. It shows which syntax is not covered by the analyzer.
+func (ds Diagnostics) CountByNodeKind() map[string]int {
+	counts := make(map[string]int)
+	for _, d := range ds {
+		counts[d.NodeKind]++
+	}
+
+	return counts
+}
+
+func (ds Diagnostics) String() string {
+	lines := make([]string, 0, len(ds))
+	for _, d := range ds {
+		lines = append(lines, d.String())
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// WriteJSON writes diagnostics as a json array.
+func (ds Diagnostics) WriteJSON(w io.Writer) error {
+	if ds == nil {
+		ds = Diagnostics{}
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+
+	return encoder.Encode(ds)
+}
+
+// Diagnostics returns every diagnostic reported while parsing, in reported order.
+func (p Parser) Diagnostics() Diagnostics {
+	return p.diagnostics
+}
+
+func (p *Parser) report(severity Severity, node ast.Node, message string) {
+	d := Diagnostic{
+		Severity: severity,
+		NodeKind: fmt.Sprintf("%T", node),
+		Message:  message,
+	}
+
+	if p.fset != nil {
+		d.Position = p.fset.Position(node.Pos())
+	}
+
+	p.diagnostics = append(p.diagnostics, d)
+}
+
+func (p *Parser) reportFileError(fileErr FileError) {
+	list := fileErr.SyntaxErrors()
+	if len(list) == 0 {
+		p.diagnostics = append(p.diagnostics, Diagnostic{
+			Severity: Error,
+			Position: token.Position{Filename: fileErr.File},
+			Message:  fileErr.Err.Error(),
+		})
+		return
+	}
+
+	for _, err := range list {
+		p.diagnostics = append(p.diagnostics, Diagnostic{
+			Severity: Error,
+			Position: err.Pos,
+			Message:  err.Msg,
+		})
+	}
+}
@@ -0,0 +1,66 @@
+package analyzer
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParser_Diagnostics(t *testing.T) {
+	root := writeModule(t, map[string]string{
+		"go.mod": "module example.com/sample\n",
+		"main.go": `package main
+
+func main() {
+	use([4]byte{})
+	(&x{}).Run()
+	interface{ Run() }(nil).Run()
+}
+
+type x struct{}
+
+func (x) Run() {}
+
+func use(interface{}) {}
+
+type number interface{ ~int | ~float64 }
+`,
+		"broken.go": `package main
+
+func broken( {
+`,
+	})
+
+	p := NewParser(root)
+	p.SetLenient(true)
+	assert.NoError(t, p.Parse())
+
+	diagnostics := p.Diagnostics()
+	assert.NotEmpty(t, diagnostics)
+
+	errors := diagnostics.AtLeast(Error)
+	if assert.NotEmpty(t, errors) {
+		assert.Contains(t, errors[0].Position.Filename, "broken.go")
+		assert.Equal(t, "", errors[0].NodeKind)
+	}
+
+	// length of array is recorded, so it is not reported
+	counts := diagnostics.CountByNodeKind()
+	assert.Equal(t, 0, counts["*ast.BasicLit"])
+	assert.Equal(t, 1, counts["*ast.BinaryExpr"])
+
+	for _, d := range diagnostics.Filter(func(d Diagnostic) bool { return d.NodeKind == "*ast.BinaryExpr" }) {
+		assert.Equal(t, Info, d.Severity)
+		assert.Equal(t, 15, d.Position.Line)
+		assert.Equal(t, 24, d.Position.Column)
+	}
+
+	var buf bytes.Buffer
+	assert.NoError(t, diagnostics.WriteJSON(&buf))
+
+	var decoded Diagnostics
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &decoded))
+	assert.Equal(t, diagnostics, decoded)
+}
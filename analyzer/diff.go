@@ -0,0 +1,255 @@
+package analyzer
+
+import (
+	"reflect"
+	"sort"
+)
+
+type ChangeKind int
+
+const (
+	Added ChangeKind = iota + 1
+	Removed
+	Changed
+)
+
+func (k ChangeKind) String() string {
+	switch k {
+	case Added:
+		return "added"
+	case Removed:
+		return "removed"
+	case Changed:
+		return "changed"
+	}
+
+	return "unknown"
+}
+
+// FunctionChange is a function which is added, removed or whose signature is changed.
+// Old is empty when the function is added, and New is empty when the function is removed.
+type FunctionChange struct {
+	Kind ChangeKind
+	Old  FunctionSnapshot
+	New  FunctionSnapshot
+}
+
+func (fc FunctionChange) Identifier() string {
+	if fc.Kind == Removed {
+		return fc.Old.Identifier
+	}
+
+	return fc.New.Identifier
+}
+
+// StructureChange is a structure which is added, removed or whose fields are changed.
+type StructureChange struct {
+	Kind ChangeKind
+	Old  StructureSnapshot
+	New  StructureSnapshot
+}
+
+func (sc StructureChange) Identifier() string {
+	if sc.Kind == Removed {
+		return sc.Old.Identifier
+	}
+
+	return sc.New.Identifier
+}
+
+// AffectedFunction is a function that calls changed function directly or transitively.
+// Depth is 1 for direct caller, and Via is the callee which makes the function affected.
+type AffectedFunction struct {
+	Identifier string
+	Depth      int
+	Via        string
+}
+
+type Impact struct {
+	Functions  []FunctionChange
+	Structures []StructureChange
+	Affected   []AffectedFunction
+}
+
+// sameSignature reports whether functions have the same types of receiver, type parameters, parameters and results.
+// Names are not compared, so renaming a parameter does not change the signature.
+func sameSignature(a, b FunctionSnapshot) bool {
+	return sameTypes(Parameters{a.Receiver}, Parameters{b.Receiver}) &&
+		sameTypes(a.TypeParams, b.TypeParams) &&
+		sameTypes(a.Parameters, b.Parameters) &&
+		sameTypes(a.Returns, b.Returns)
+}
+
+// sameTypes compares types of parameters in order. Type as written is compared when there is no Typ,
+// like snapshots of older versions.
+func sameTypes(a, b Parameters) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for index := range a {
+		if a[index].Typ != nil && b[index].Typ != nil {
+			if !a[index].Typ.Equal(*b[index].Typ) {
+				return false
+			}
+			continue
+		}
+
+		if a[index].Type != b[index].Type || a[index].IsPointer != b[index].IsPointer || a[index].IsVariadic != b[index].IsVariadic {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Impact compares old snapshot with current analysis.
+func (p Parser) Impact(old Snapshot) Impact {
+	return Diff(old, p.Snapshot())
+}
+
+// Diff reports changes between two snapshots, and every function which is affected by the changes.
+// Methods of changed structure are treated as changed, so their callers are affected too.
+func Diff(old, new Snapshot) (impact Impact) {
+	oldFunctions := make(map[string]FunctionSnapshot)
+	for _, f := range old.Functions {
+		oldFunctions[f.Identifier] = f
+	}
+
+	newFunctions := make(map[string]FunctionSnapshot)
+	for _, f := range new.Functions {
+		newFunctions[f.Identifier] = f
+
+		o, ok := oldFunctions[f.Identifier]
+		switch {
+		case !ok:
+			impact.Functions = append(impact.Functions, FunctionChange{Kind: Added, New: f})
+		case !sameSignature(o, f):
+			impact.Functions = append(impact.Functions, FunctionChange{Kind: Changed, Old: o, New: f})
+		}
+	}
+
+	for _, f := range old.Functions {
+		if _, ok := newFunctions[f.Identifier]; !ok {
+			impact.Functions = append(impact.Functions, FunctionChange{Kind: Removed, Old: f})
+		}
+	}
+
+	oldStructures := make(map[string]StructureSnapshot)
+	for _, s := range old.Structures {
+		oldStructures[s.Identifier] = s
+	}
+
+	newStructures := make(map[string]StructureSnapshot)
+	for _, s := range new.Structures {
+		newStructures[s.Identifier] = s
+
+		o, ok := oldStructures[s.Identifier]
+		switch {
+		case !ok:
+			impact.Structures = append(impact.Structures, StructureChange{Kind: Added, New: s})
+		case !reflect.DeepEqual(o.Fields, s.Fields):
+			impact.Structures = append(impact.Structures, StructureChange{Kind: Changed, Old: o, New: s})
+		}
+	}
+
+	for _, s := range old.Structures {
+		if _, ok := newStructures[s.Identifier]; !ok {
+			impact.Structures = append(impact.Structures, StructureChange{Kind: Removed, Old: s})
+		}
+	}
+
+	sort.Slice(impact.Functions, func(i, j int) bool {
+		return impact.Functions[i].Identifier() < impact.Functions[j].Identifier()
+	})
+	sort.Slice(impact.Structures, func(i, j int) bool {
+		return impact.Structures[i].Identifier() < impact.Structures[j].Identifier()
+	})
+
+	changed := make([]string, 0)
+	for _, fc := range impact.Functions {
+		changed = append(changed, fc.Identifier())
+	}
+
+	for _, sc := range impact.Structures {
+		if sc.Kind != Removed {
+			changed = append(changed, sc.New.Methods...)
+		}
+		if sc.Kind != Added {
+			changed = append(changed, sc.Old.Methods...)
+		}
+	}
+
+	// callers of removed function only exist in old snapshot
+	callers := reverseCallGraph(new)
+	for callee, cs := range reverseCallGraph(old) {
+		for _, caller := range cs {
+			if _, ok := newFunctions[caller]; ok {
+				callers[callee] = append(callers[callee], caller)
+			}
+		}
+	}
+
+	impact.Affected = affectedFunctions(changed, callers)
+	return
+}
+
+// reverseCallGraph returns callers of each function.
+func reverseCallGraph(s Snapshot) map[string][]string {
+	callers := make(map[string][]string)
+	for _, call := range s.Calls {
+		if call.Caller == "" || call.FuncValue {
+			continue
+		}
+
+		callers[call.Callee] = append(callers[call.Callee], call.Caller)
+	}
+
+	return callers
+}
+
+// affectedFunctions walks callers in breadth first order, so Depth is the shortest distance from changed functions.
+func affectedFunctions(changed []string, callers map[string][]string) (affected []AffectedFunction) {
+	visited := make(map[string]bool)
+	for _, identifier := range changed {
+		visited[identifier] = true
+	}
+
+	queue := make([]AffectedFunction, 0)
+	for _, identifier := range changed {
+		queue = append(queue, AffectedFunction{Identifier: identifier})
+	}
+
+	for len(queue) != 0 {
+		current := queue[0]
+		queue = queue[1:]
+
+		cs := append([]string(nil), callers[current.Identifier]...)
+		sort.Strings(cs)
+
+		for _, caller := range cs {
+			if visited[caller] {
+				continue
+			}
+			visited[caller] = true
+
+			af := AffectedFunction{
+				Identifier: caller,
+				Depth:      current.Depth + 1,
+				Via:        current.Identifier,
+			}
+			affected = append(affected, af)
+			queue = append(queue, af)
+		}
+	}
+
+	sort.SliceStable(affected, func(i, j int) bool {
+		if affected[i].Depth != affected[j].Depth {
+			return affected[i].Depth < affected[j].Depth
+		}
+
+		return affected[i].Identifier < affected[j].Identifier
+	})
+
+	return
+}
@@ -0,0 +1,215 @@
+package analyzer
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func parseModuleSnapshot(t *testing.T, files map[string]string) Snapshot {
+	files["go.mod"] = "module example.com/sample\n"
+	root := writeModule(t, files)
+
+	p := NewParser(root)
+	p.SetRecursive(true)
+	assert.NoError(t, p.Parse())
+
+	return p.Snapshot()
+}
+
+func TestDiff(t *testing.T) {
+	old := parseModuleSnapshot(t, map[string]string{
+		"main.go": `package main
+
+func main() {
+	handle()
+	unrelated()
+}
+
+func handle() {
+	validate(1)
+}
+
+func validate(a int) {}
+
+func unrelated() {}
+
+func removed() {}
+
+type Config struct {
+	Name string
+}
+
+func (c Config) Load() {}
+
+func load() {
+	Config{}.Load()
+}
+`,
+	})
+
+	new := parseModuleSnapshot(t, map[string]string{
+		"main.go": `package main
+
+func main() {
+	handle()
+	unrelated()
+}
+
+func handle() {
+	validate(1, 2)
+}
+
+func validate(a, b int) {}
+
+func unrelated() {}
+
+func added() {}
+
+type Config struct {
+	Name string
+	Path string
+}
+
+func (c Config) Load() {}
+
+func load() {
+	Config{}.Load()
+}
+`,
+	})
+
+	impact := Diff(old, new)
+
+	changes := make(map[string]ChangeKind)
+	for _, fc := range impact.Functions {
+		changes[fc.Identifier()] = fc.Kind
+	}
+	assert.Equal(t, map[string]ChangeKind{
+		"example.com/sample.added":    Added,
+		"example.com/sample.removed":  Removed,
+		"example.com/sample.validate": Changed,
+	}, changes)
+
+	if assert.Len(t, impact.Structures, 1) {
+		assert.Equal(t, Changed, impact.Structures[0].Kind)
+		assert.Equal(t, "example.com/sample.Config", impact.Structures[0].Identifier())
+	}
+
+	assert.Equal(t, []AffectedFunction{{
+		Identifier: "example.com/sample.handle",
+		Depth:      1,
+		Via:        "example.com/sample.validate",
+	}, {
+		Identifier: "example.com/sample.load",
+		Depth:      1,
+		Via:        "example.com/sample.Config.Load",
+	}, {
+		Identifier: "example.com/sample.main",
+		Depth:      2,
+		Via:        "example.com/sample.handle",
+	}}, impact.Affected)
+}
+
+func TestDiff_Same(t *testing.T) {
+	files := map[string]string{
+		"main.go": `package main
+
+func main() {}
+`,
+	}
+
+	s := parseModuleSnapshot(t, files)
+	impact := Diff(s, s)
+
+	assert.Empty(t, impact.Functions)
+	assert.Empty(t, impact.Structures)
+	assert.Empty(t, impact.Affected)
+}
+
+func TestDiff_NestedCalls(t *testing.T) {
+	src := `package main
+
+func main() {
+	println(check(%s))
+}
+
+func spawn() {
+	go func() {
+		check(%s)
+	}()
+}
+
+func register(f func()) {}
+
+func handle() {
+	register(func() {
+		defer check(%s)
+	})
+}
+
+func check(%s) bool {
+	return true
+}
+`
+
+	old := parseModuleSnapshot(t, map[string]string{
+		"main.go": fmt.Sprintf(src, "1", "1", "1", "a int"),
+	})
+	new := parseModuleSnapshot(t, map[string]string{
+		"main.go": fmt.Sprintf(src, "1, 2", "1, 2", "1, 2", "a, b int"),
+	})
+
+	// calls in arguments and function literals are written in main, spawn and handle
+	assert.Equal(t, []AffectedFunction{{
+		Identifier: "example.com/sample.handle",
+		Depth:      1,
+		Via:        "example.com/sample.check",
+	}, {
+		Identifier: "example.com/sample.main",
+		Depth:      1,
+		Via:        "example.com/sample.check",
+	}, {
+		Identifier: "example.com/sample.spawn",
+		Depth:      1,
+		Via:        "example.com/sample.check",
+	}}, Diff(old, new).Affected)
+}
+
+func TestDiff_RenameParameter(t *testing.T) {
+	source := `package main
+
+import "example.com/sample/util"
+
+func main() {
+	handle(nil, 1)
+}
+
+func handle(s *util.Server, count int) (err error) {
+	return nil
+}
+`
+	util := "package util\n\ntype Server struct{}\n"
+
+	old := parseModuleSnapshot(t, map[string]string{"main.go": source, "util/util.go": util})
+
+	// only names of parameters and results are changed
+	renamed := strings.NewReplacer("s *util.Server, count int", "server *util.Server, n int", "(err error)", "(e error)").Replace(source)
+	new := parseModuleSnapshot(t, map[string]string{"main.go": renamed, "util/util.go": util})
+
+	impact := Diff(old, new)
+	assert.Empty(t, impact.Functions)
+	assert.Empty(t, impact.Affected)
+
+	// type of parameter is changed
+	changed := strings.Replace(source, "s *util.Server", "s util.Server", 1)
+	new = parseModuleSnapshot(t, map[string]string{"main.go": changed, "util/util.go": util})
+
+	impact = Diff(old, new)
+	if assert.Len(t, impact.Functions, 1) {
+		assert.Equal(t, "example.com/sample.handle", impact.Functions[0].Identifier())
+	}
+	assert.Equal(t, []AffectedFunction{{Identifier: "example.com/sample.main", Depth: 1, Via: "example.com/sample.handle"}}, impact.Affected)
+}
@@ -0,0 +1,114 @@
+package analyzer
+
+import (
+	"go/ast"
+	"sort"
+	"strings"
+)
+
+// SetDynamicDispatch makes Parse link method calls on interface values to every method that implements it.
+// Those calls are added into FuncCalls with IsPossible, next to the unresolved call on the interface.
+func (p *Parser) SetDynamicDispatch(dynamicDispatch bool) {
+	p.dynamicDispatch = dynamicDispatch
+}
+
+func (p *Parser) linkDynamicCalls() {
+	if !p.dynamicDispatch {
+		return
+	}
+
+	// implementations may be parsed after the call, so a call is linked again to the declarations
+	// that are not linked to it yet
+	type possibleCall struct {
+		expr   *ast.CallExpr
+		callee string
+	}
+	linked := make(map[possibleCall]bool)
+	for _, call := range p.functionCalls {
+		if call.IsPossible {
+			linked[possibleCall{call.expr, call.Callee}] = true
+		}
+	}
+
+	implementations := make(map[string][]Implementation)
+	possible := make([]FunctionCall, 0)
+	for _, call := range p.functionCalls {
+		// only method calls on values can be dispatched dynamically
+		if call.FunctionDeclaration != nil || call.IsPossible || call.method == "" {
+			continue
+		}
+
+		index := strings.LastIndex(call.Callee, ".")
+		if index < 0 {
+			continue
+		}
+		iface := call.Callee[:index]
+
+		if _, ok := implementations[iface]; !ok {
+			implementations[iface] = p.Implementations(iface)
+		}
+
+		calls := make([]FunctionCall, 0)
+		for _, implementation := range implementations[iface] {
+			decl, ok := p.methodDeclaration(implementation.Type, call.method, make(map[string]bool))
+			if !ok {
+				continue
+			}
+
+			fc := call
+			fc.Callee = decl.Identifier()
+			fc.FunctionDeclaration = decl
+			fc.IsPossible = true
+			calls = append(calls, fc)
+		}
+
+		// embedded types may share a method, so a declaration is linked once for a call
+		sort.SliceStable(calls, func(i, j int) bool {
+			return calls[i].Callee < calls[j].Callee
+		})
+		for index, fc := range calls {
+			if (index > 0 && calls[index-1].Callee == fc.Callee) || linked[possibleCall{fc.expr, fc.Callee}] {
+				continue
+			}
+
+			fc.FunctionDeclaration.Calls = append(fc.FunctionDeclaration.Calls, fc)
+			possible = append(possible, fc)
+		}
+	}
+
+	p.functionCalls = append(p.functionCalls, possible...)
+}
+
+// methodDeclaration finds method of the type, which is declared on it or promoted from its embedded fields.
+func (p Parser) methodDeclaration(typ, name string, visited map[string]bool) (decl *FunctionStatement, ok bool) {
+	if visited[typ] {
+		return
+	}
+	visited[typ] = true
+
+	if decl, ok = p.functionsByName[typ+"."+name]; ok {
+		return
+	}
+
+	strct, isStructure := p.structureTypes[typ]
+	if !isStructure {
+		return
+	}
+
+	pkg := strct.PkgName
+	if strct.ImportPath != "" {
+		pkg = strct.ImportPath
+	}
+
+	for _, field := range strct.Parameters {
+		if field.Name != "" {
+			continue
+		}
+
+		if decl, ok = p.methodDeclaration(qualifyType(pkg, p.importTable[strct.File], field.Type), name, visited); ok {
+			return
+		}
+	}
+
+	return
+}
@@ -0,0 +1,203 @@
+package analyzer
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const dispatchTestFile = `package main
+
+import "io"
+
+type Handler interface {
+	Serve(path string) error
+}
+
+type Base struct{}
+
+func (b Base) Serve(path string) error {
+	return nil
+}
+
+type Static struct {
+	Base
+}
+
+type API struct{}
+
+func (a *API) Serve(path string) error {
+	return nil
+}
+
+type Buffer struct{}
+
+func (b *Buffer) Write(p []byte) (n int, err error) {
+	return len(p), nil
+}
+
+func handle(h Handler) {
+	h.Serve("/")
+}
+
+func write(w io.Writer) {
+	w.Write(nil)
+}
+`
+
+func TestParser_SetDynamicDispatch(t *testing.T) {
+	root := writeModule(t, map[string]string{
+		"go.mod":  "module example.com/sample\n",
+		"main.go": dispatchTestFile,
+	})
+
+	t.Run("동적 디스패치를 사용하지 않는 경우", func(t *testing.T) {
+		p := NewParser(root)
+		p.SetRecursive(true)
+		assert.NoError(t, p.Parse())
+
+		for _, call := range p.FuncCalls() {
+			assert.False(t, call.IsPossible)
+		}
+	})
+
+	t.Run("인터페이스를 구현하는 메소드로 연결하는 경우", func(t *testing.T) {
+		p := NewParser(root)
+		p.SetRecursive(true)
+		p.SetDynamicDispatch(true)
+		assert.NoError(t, p.Parse())
+
+		possible := make(map[string]string)
+		for _, call := range p.Snapshot().Calls {
+			if call.Possible {
+				possible[call.Callee] = call.Caller
+			}
+		}
+
+		assert.Equal(t, map[string]string{
+			"example.com/sample.Base.Serve":   "example.com/sample.handle",
+			"example.com/sample.API.Serve":    "example.com/sample.handle",
+			"example.com/sample.Buffer.Write": "example.com/sample.write",
+		}, possible)
+
+		serve, ok := p.Function("example.com/sample.Base.Serve")
+		if assert.True(t, ok) && assert.Len(t, serve.Calls, 1) {
+			assert.True(t, serve.Calls[0].IsPossible)
+		}
+	})
+
+	t.Run("소스 코드를 파싱하는 경우", func(t *testing.T) {
+		p := NewParser(filepath.Join(root, "main.go"))
+		p.SetDynamicDispatch(true)
+		assert.NoError(t, p.ParseFile(dispatchTestFile))
+
+		possible := make(map[string]string)
+		for _, call := range p.Snapshot().Calls {
+			if call.Possible {
+				possible[call.Callee] = call.Caller
+			}
+		}
+
+		assert.Equal(t, map[string]string{
+			"example.com/sample.Base.Serve":   "example.com/sample.handle",
+			"example.com/sample.API.Serve":    "example.com/sample.handle",
+			"example.com/sample.Buffer.Write": "example.com/sample.write",
+		}, possible)
+
+		// possible calls are added once, even when ParseFile links calls again
+		p.path = filepath.Join(root, "other.go")
+		assert.NoError(t, p.ParseFile("package main\n\nfunc other() {}\n"))
+
+		serve, ok := p.Function("example.com/sample.API.Serve")
+		if assert.True(t, ok) && assert.Len(t, serve.Calls, 1) {
+			assert.True(t, serve.Calls[0].IsPossible)
+		}
+	})
+}
+
+func TestDiff_DynamicDispatch(t *testing.T) {
+	parse := func(source string) Snapshot {
+		root := writeModule(t, map[string]string{
+			"go.mod":  "module example.com/sample\n",
+			"main.go": source,
+		})
+
+		p := NewParser(root)
+		p.SetRecursive(true)
+		p.SetDynamicDispatch(true)
+		assert.NoError(t, p.Parse())
+
+		return p.Snapshot()
+	}
+
+	old := parse(dispatchTestFile)
+	new := parse(strings.Replace(dispatchTestFile, "type API struct{}", "type API struct {\n\tName string\n}", 1))
+
+	assert.Equal(t, []AffectedFunction{{
+		Identifier: "example.com/sample.handle",
+		Depth:      1,
+		Via:        "example.com/sample.API.Serve",
+	}}, Diff(old, new).Affected)
+}
+
+func TestParser_ParseFile_DynamicDispatch(t *testing.T) {
+	root := writeModule(t, map[string]string{
+		"go.mod": "module example.com/sample\n",
+	})
+
+	p := NewParser(filepath.Join(root, "handler.go"))
+	p.SetDynamicDispatch(true)
+	assert.NoError(t, p.ParseFile(`package main
+
+type Handler interface {
+	Serve()
+}
+
+func handle(h Handler) {
+	h.Serve()
+}
+
+func run() {
+	a := newAPI()
+	a.Serve()
+}
+`))
+
+	// implementation and the function returning it are parsed after the calls
+	p.path = filepath.Join(root, "api.go")
+	assert.NoError(t, p.ParseFile(`package main
+
+type API struct{}
+
+func (API) Serve() {}
+
+func newAPI() API {
+	return API{}
+}
+`))
+
+	assert.Equal(t, []Implementation{{Type: "example.com/sample.API", Interface: "example.com/sample.Handler"}}, p.Implementations("example.com/sample.Handler"))
+
+	callers := make(map[string][]bool)
+	for _, call := range p.FuncCalls() {
+		if call.FunctionDeclaration != nil && call.FunctionDeclaration.Identifier() == "example.com/sample.API.Serve" {
+			callers[call.Parent.Identifier()] = append(callers[call.Parent.Identifier()], call.IsPossible)
+		}
+	}
+
+	assert.Equal(t, map[string][]bool{
+		"example.com/sample.handle": {true},
+		"example.com/sample.run":    {false},
+	}, callers)
+
+	// possible calls are added once, even when ParseFile links calls again
+	p.path = filepath.Join(root, "other.go")
+	assert.NoError(t, p.ParseFile("package main\n\nfunc other() {}\n"))
+
+	serve, ok := p.Function("example.com/sample.API.Serve")
+	if assert.True(t, ok) {
+		assert.Len(t, serve.Calls, 2)
+	}
+}
@@ -0,0 +1,33 @@
+package analyzer
+
+import (
+	"go/scanner"
+)
+
+// FileError is an error occurred while parsing a file.
+type FileError struct {
+	File string
+	Err  error
+}
+
+func (e FileError) Error() string {
+	// syntax error already has position
+	if list := e.SyntaxErrors(); len(list) != 0 {
+		return list.Error()
+	}
+
+	return e.File + ": " + e.Err.Error()
+}
+
+func (e FileError) Unwrap() error {
+	return e.Err
+}
+
+// SyntaxErrors returns every syntax error of the file. In lenient mode, it has all errors not only the first ten.
+func (e FileError) SyntaxErrors() scanner.ErrorList {
+	if list, ok := e.Err.(scanner.ErrorList); ok {
+		return list
+	}
+
+	return nil
+}
@@ -0,0 +1,88 @@
+package analyzer
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParser_ParseError(t *testing.T) {
+	files := map[string]string{
+		"go.mod": "module example.com/sample\n",
+		"main.go": `package main
+
+func main() {
+	ok()
+}
+`,
+		"ok.go": `package main
+
+func ok() {}
+`,
+		"broken.go": `package main
+
+func broken( {
+	var x =
+}
+`,
+	}
+
+	t.Run("에러가 발생하면 에러를 리턴하는 경우", func(t *testing.T) {
+		p := NewParser(writeModule(t, files))
+
+		err := p.Parse()
+		if assert.Error(t, err) {
+			assert.IsType(t, FileError{}, err)
+		}
+	})
+
+	t.Run("모듈에서 에러가 발생하면 에러를 리턴하는 경우", func(t *testing.T) {
+		p := NewParser(writeModule(t, files))
+		p.SetRecursive(true)
+
+		assert.Error(t, p.Parse())
+	})
+
+	t.Run("lenient 모드에서는 파싱된 파일을 계속 분석하는 경우", func(t *testing.T) {
+		p := NewParser(writeModule(t, files))
+		p.SetLenient(true)
+
+		assert.NoError(t, p.Parse())
+
+		ok, found := p.Function("example.com/sample.ok")
+		if assert.True(t, found) {
+			assert.Len(t, ok.Calls, 1)
+		}
+
+		if assert.Len(t, p.Errors(), 1) {
+			fileErr := p.Errors()[0]
+			assert.Contains(t, fileErr.File, "broken.go")
+			assert.Greater(t, len(fileErr.SyntaxErrors()), 1)
+		}
+	})
+
+	t.Run("go.mod가 없는 경우", func(t *testing.T) {
+		p := NewParser(t.TempDir())
+		p.SetRecursive(true)
+
+		assert.Error(t, p.Parse())
+	})
+}
+
+func TestParser_ParseFileError(t *testing.T) {
+	dir := t.TempDir()
+
+	p := NewParser(filepath.Join(dir, "broken.go"))
+	assert.Error(t, p.ParseFile("package main\nfunc ("))
+
+	p = NewParser(filepath.Join(dir, "broken.go"))
+	p.SetLenient(true)
+	assert.NoError(t, p.ParseFile("package main\nfunc ("))
+	assert.Len(t, p.Errors(), 1)
+
+	p = NewParser(filepath.Join(dir, "ok.go"))
+	assert.NoError(t, p.ParseFile("package main\nfunc main() {}\n"))
+	_, ok := p.Function("main.main")
+	assert.True(t, ok)
+}
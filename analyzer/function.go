@@ -3,6 +3,8 @@ package analyzer
 import (
 	"fmt"
 	"go/ast"
+	"go/types"
+	"strconv"
 	"strings"
 )
 
@@ -12,9 +14,18 @@ type Field interface {
 
 type Selector struct {
 	Parent           string
-	ParentType       string
+	ParentType       string // type of Parent, or results when Parent is a function. It is exact in typed mode
 	Field            Field
 	ImportedSelector bool
+	Chain            SelectorChain // every segment of the selector, from its operand
+
+	// Object, TypeInfo and Selection are set in typed mode. Object is the selected field, method or
+	// member of package, and Selection is set when it is selected from a value, like s.Name.
+	Object    types.Object
+	TypeInfo  types.Type
+	Selection *types.Selection
+
+	expr *ast.SelectorExpr
 }
 
 func (s Selector) String() string {
@@ -54,18 +65,41 @@ func (v Variable) String() string {
 
 type FunctionCall struct {
 	Package             string
+	ImportPath          string
+	Callee              string
 	Parent              *FunctionStatement
 	Name                string
+	TypeArgs            []string // type arguments of generic function, like int of Map[int](xs)
 	Parameters          Parameters
+	Chain               SelectorChain // segments of the call, like c, Request(), Header and Get("a")
+	Selector            *Selector     // called function when it is selected, like c.Request().Header.Get
 	FunctionDeclaration *FunctionStatement
 	IsImportedFunction  bool
+	IsPossible          bool // call through interface, which may call FunctionDeclaration at runtime
+	IsFuncValue         bool // call of variable or parameter, like f of f := func() {}; f(). Callee is empty
 	File                string
 	FilePath            string
 	Pos                 int
 	LineNumber          int
+
+	// Object, TypeInfo and Selection are set in typed mode. Object is the called *types.Func,
+	// and Selection is set when the function is selected from a value, like x.Start().
+	Object    types.Object
+	TypeInfo  types.Type
+	Selection *types.Selection
+
+	expr     *ast.CallExpr
+	receiver *typeOrigin // type of the value that method is called on, resolved after parsing
+	method   string
+	generic  string // callee when the call is instantiation of generic function, resolved after parsing
 }
 
+// Identifier returns resolved callee identifier if exists, or else name of called function.
 func (fc FunctionCall) Identifier() string {
+	if fc.Callee != "" {
+		return fc.Callee
+	}
+
 	return fc.Name
 }
 
@@ -80,18 +114,26 @@ func (fc FunctionCall) String() string {
 type FunctionStatement struct {
 	Path       string
 	Package    string
+	ImportPath string
 	Receiver   Parameter
 	Name       string
+	TypeParams Parameters
 	Parameters Parameters
 	Returns    Parameters
 	Body       *ast.BlockStmt
 	SourceCode SourceCode
 	Calls      []FunctionCall
 	Node       ast.Node
+	Ordinal    int // order of init function in its package, from 1. It is 0 for other functions
 }
 
+// Identifier returns identifier of the function, like example.com/sample.Server.Start.
+// A package can have several init functions, so they are identified with their order, like example.com/sample.init#1.
 func (fs FunctionStatement) Identifier() (idf string) {
 	idfs := []string{fs.Package}
+	if fs.ImportPath != "" {
+		idfs[0] = fs.ImportPath
+	}
 
 	if fs.Receiver.Type != "" {
 		idfs = append(idfs, fs.Receiver.Type)
@@ -99,6 +141,9 @@ func (fs FunctionStatement) Identifier() (idf string) {
 	idfs = append(idfs, fs.Name)
 
 	idf = strings.Join(idfs, ".")
+	if fs.Ordinal != 0 {
+		idf += "#" + strconv.Itoa(fs.Ordinal)
+	}
 	return
 }
 
@@ -115,5 +160,5 @@ func (fs FunctionStatement) String() string {
 		returns = " " + fs.Returns.String()
 	}
 
-	return fmt.Sprintf("func %s%s%s%s", receiver, fs.Name, fs.Parameters, returns)
+	return fmt.Sprintf("func %s%s%s%s%s", receiver, fs.Name, fs.TypeParams.typeParamsString(), fs.Parameters, returns)
 }
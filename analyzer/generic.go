@@ -0,0 +1,134 @@
+package analyzer
+
+import (
+	"go/ast"
+	"strings"
+)
+
+// ParseTypeParams parses type parameter list. Type of each parameter is its constraint, like any or ~int | ~string.
+// It returns nil when fields is nil, which means the declaration is not generic.
+func (p *Parser) ParseTypeParams(pkgName string, fields *ast.FieldList) (typeParams Parameters) {
+	if fields == nil {
+		return
+	}
+
+	for _, field := range fields.List {
+		constraint := p.ParseType(pkgName, field.Type).String()
+		typ := p.ParseTyp(field.Type)
+
+		for index, name := range field.Names {
+			typeParams = append(typeParams, Parameter{
+				Pkg:                  pkgName,
+				Name:                 name.Name,
+				Type:                 constraint,
+				IsMultipleParameters: index+1 != len(field.Names),
+				Typ:                  &typ,
+			})
+		}
+	}
+
+	return
+}
+
+// ParseTypeArgs returns generic function or type, and its type arguments of an instantiation like Map[int, string].
+// ok is false when x is not an index expression, which may be an instantiation.
+func (p *Parser) ParseTypeArgs(pkgName string, x ast.Expr) (generic ast.Expr, typeArgs []string, ok bool) {
+	generic, indices, ok := instantiation(x)
+	if !ok {
+		return
+	}
+
+	for _, index := range indices {
+		typeArgs = append(typeArgs, p.ParseType(pkgName, index).String())
+	}
+
+	return
+}
+
+func instantiation(x ast.Expr) (generic ast.Expr, indices []ast.Expr, ok bool) {
+	switch x2 := x.(type) {
+	case *ast.IndexExpr:
+		return x2.X, []ast.Expr{x2.Index}, true
+	case *ast.IndexListExpr:
+		return x2.X, x2.Indices, true
+	}
+
+	return
+}
+
+// typeParamNames returns names of the type parameters.
+func (ps Parameters) typeParamNames() map[string]bool {
+	names := make(map[string]bool, len(ps))
+	for _, prm := range ps {
+		names[prm.Name] = true
+	}
+
+	return names
+}
+
+// typeParamNames returns names of type parameters that f declares, and that its receiver binds,
+// like T of func (l *List[T]) Push(v T).
+func (fs *FunctionStatement) typeParamNames() map[string]bool {
+	names := fs.TypeParams.typeParamNames()
+	if fs.Receiver.Typ == nil {
+		return names
+	}
+
+	receiver := *fs.Receiver.Typ
+	if receiver.Kind == PointerType {
+		receiver = *receiver.Elem
+	}
+
+	if receiver.Kind == InstanceType {
+		for _, typeArg := range receiver.TypeArgs {
+			if typeArg.Kind == NamedType && typeArg.Package == "" {
+				names[typeArg.Name] = true
+			}
+		}
+	}
+
+	return names
+}
+
+// typeParamsString returns type parameters in brackets, or empty string when there is no type parameter.
+func (ps Parameters) typeParamsString() string {
+	if len(ps) == 0 {
+		return ""
+	}
+
+	s := ps.String()
+	return "[" + s[1:len(s)-1] + "]"
+}
+
+// genericBase drops type arguments from instantiated type, like example.com/sample.List[int].
+func genericBase(typ string) string {
+	if !strings.HasSuffix(typ, "]") || strings.HasPrefix(typ, "[") || strings.HasPrefix(typ, "map[") {
+		return typ
+	}
+
+	if index := strings.Index(typ, "["); index > 0 {
+		return typ[:index]
+	}
+
+	return typ
+}
+
+// splitTypeArgs splits type arguments by commas, which are not in brackets or parentheses.
+func splitTypeArgs(args string) (typeArgs []string) {
+	depth, start := 0, 0
+	for index, r := range args {
+		switch r {
+		case '[', '(', '{':
+			depth++
+		case ']', ')', '}':
+			depth--
+		case ',':
+			if depth == 0 {
+				typeArgs = append(typeArgs, strings.TrimSpace(args[start:index]))
+				start = index + 1
+			}
+		}
+	}
+
+	return append(typeArgs, strings.TrimSpace(args[start:]))
+}
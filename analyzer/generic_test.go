@@ -0,0 +1,121 @@
+package analyzer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParser_ParseGeneric(t *testing.T) {
+	root := writeModule(t, map[string]string{
+		"go.mod": "module example.com/sample\n",
+		"main.go": `package main
+
+import "example.com/sample/util"
+
+type Number interface {
+	~int | ~float64
+}
+
+func Map[T, U any](xs []T, ys []U) []U {
+	return nil
+}
+
+func Sum[T Number](xs ...T) T {
+	var sum T
+	return sum
+}
+
+func main() {
+	handlers := []func(){}
+	handlers[0]()
+
+	ys := Map[int, string](nil, nil)
+	_ = ys
+
+	total := Sum[int](1, 2)
+	_ = total
+
+	l := util.NewList[int]()
+	l.Push(1)
+
+	var p util.Pair[string, int]
+	p.Swap()
+}
+`,
+		"util/util.go": `package util
+
+type List[T any] struct {
+	items []T
+}
+
+func NewList[T any]() *List[T] {
+	return &List[T]{}
+}
+
+func (l *List[T]) Push(v T) {}
+
+type Pair[K comparable, V any] struct {
+	Key   K
+	Value V
+}
+
+func (p Pair[K, V]) Swap() {}
+`,
+	})
+
+	for _, typed := range []bool{false, true} {
+		p := NewParser(root)
+		p.SetRecursive(true)
+		p.SetTyped(typed)
+		assert.NoError(t, p.Parse())
+
+		m, ok := p.Function("example.com/sample.Map")
+		if assert.True(t, ok) {
+			assert.Equal(t, Parameters{
+				{Pkg: "main", Name: "T", Type: "any", IsMultipleParameters: true, Typ: named("any")},
+				{Pkg: "main", Name: "U", Type: "any", Typ: named("any")},
+			}, m.TypeParams)
+			assert.Equal(t, "func Map[T, U any](xs []T, ys []U) []U", m.String())
+
+			if assert.Len(t, m.Calls, 1) {
+				assert.Equal(t, []string{"int", "string"}, m.Calls[0].TypeArgs)
+			}
+		}
+
+		sum, ok := p.Function("example.com/sample.Sum")
+		if assert.True(t, ok) {
+			assert.Equal(t, Parameters{{Pkg: "main", Name: "T", Type: "Number", Typ: &Typ{Kind: NamedType, Path: "example.com/sample", Name: "Number"}}}, sum.TypeParams)
+			if assert.Len(t, sum.Calls, 1) {
+				assert.Equal(t, []string{"int"}, sum.Calls[0].TypeArgs)
+			}
+		}
+
+		for name, calls := range map[string]int{
+			"example.com/sample/util.NewList":   1,
+			"example.com/sample/util.List.Push": 1,
+			"example.com/sample/util.Pair.Swap": 1,
+		} {
+			f, ok := p.Function(name)
+			if assert.True(t, ok, name) {
+				assert.Len(t, f.Calls, calls, name)
+			}
+		}
+
+		for _, call := range p.FuncCalls() {
+			if call.Name == "main.handlers[0]" {
+				assert.Nil(t, call.FunctionDeclaration)
+				assert.Empty(t, call.TypeArgs)
+			}
+		}
+
+		for _, strct := range p.Structures() {
+			if strct.Name == "Pair" {
+				assert.Equal(t, Parameters{
+					{Pkg: "util", Name: "K", Type: "comparable", Typ: named("comparable")},
+					{Pkg: "util", Name: "V", Type: "any", Typ: named("any")},
+				}, strct.TypeParams)
+			}
+		}
+	}
+}
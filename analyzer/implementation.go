@@ -0,0 +1,418 @@
+package analyzer
+
+import (
+	"go/ast"
+	"go/importer"
+	"go/token"
+	"go/types"
+	"sort"
+	"strings"
+)
+
+// Implementation is a named type which implements an interface.
+// When Pointer is true, only pointer of the type implements it, because some methods have pointer receiver.
+type Implementation struct {
+	Type      string
+	Interface string
+	Pointer   bool
+}
+
+func (i Implementation) String() string {
+	typ := i.Type
+	if i.Pointer {
+		typ = "*" + typ
+	}
+
+	return typ + " implements " + i.Interface
+}
+
+// signature is signature of a method. typed is the signature by the type checker, and it is nil for methods
+// that are parsed in syntactic mode. text is written in the form of signatureOf.
+type signature struct {
+	text  string
+	typed *types.Signature
+}
+
+// identical compares signatures by the type checker when both are typed, and by text otherwise.
+// Names of parameters are not compared.
+func (s signature) identical(o signature) bool {
+	if s.typed != nil && o.typed != nil {
+		return types.Identical(s.typed, o.typed)
+	}
+
+	return s.text == o.text
+}
+
+// methodSet is signatures of methods, keyed by name of the method.
+type methodSet map[string]signature
+
+// typeMethods is method set of a named type T, and of *T.
+type typeMethods struct {
+	value   methodSet
+	pointer methodSet
+}
+
+// Implementations returns named types that implement the interface. Interfaces out of the parsed packages,
+// like io.Reader, are loaded from source of the package.
+func (p Parser) Implementations(iface string) (implementations []Implementation) {
+	if _, ok := p.interfaceTypes[iface]; ok {
+		for _, implementation := range p.implementations {
+			if implementation.Interface == iface {
+				implementations = append(implementations, implementation)
+			}
+		}
+
+		return
+	}
+
+	methods, ok := p.interfaceMethods(iface, make(map[string]bool))
+	if !ok {
+		return
+	}
+
+	return p.implementationsOf(iface, methods, p.typeMethodSets())
+}
+
+// Implements returns interfaces that the type implements. typ is identifier of named type,
+// and "*" prefix means pointer of the type, which has methods of both receivers.
+func (p Parser) Implements(typ string) (implementations []Implementation) {
+	pointer := strings.HasPrefix(typ, "*")
+	typ = strings.TrimPrefix(typ, "*")
+
+	for _, implementation := range p.implementations {
+		if implementation.Type == typ && (pointer || !implementation.Pointer) {
+			implementations = append(implementations, implementation)
+		}
+	}
+
+	return
+}
+
+// linkImplementations finds implementations of every parsed interface. Interfaces without methods are skipped,
+// because every type implements them.
+func (p *Parser) linkImplementations() {
+	if p.importer == nil {
+		p.importer = newSourceImporter()
+	}
+
+	sets := p.typeMethodSets()
+
+	p.implementations = make([]Implementation, 0)
+	for _, iface := range p.Interfaces() {
+		methods, ok := p.interfaceMethods(iface.Identifier(), make(map[string]bool))
+		if !ok {
+			continue
+		}
+
+		p.implementations = append(p.implementations, p.implementationsOf(iface.Identifier(), methods, sets)...)
+	}
+}
+
+func (p Parser) implementationsOf(iface string, methods methodSet, sets map[string]typeMethods) (implementations []Implementation) {
+	if len(methods) == 0 {
+		return
+	}
+
+	for typ, set := range sets {
+		if set.value.contains(methods) {
+			implementations = append(implementations, Implementation{Type: typ, Interface: iface})
+		} else if set.pointer.contains(methods) {
+			implementations = append(implementations, Implementation{Type: typ, Interface: iface, Pointer: true})
+		}
+	}
+
+	sort.Slice(implementations, func(i, j int) bool {
+		return implementations[i].Type < implementations[j].Type
+	})
+
+	return
+}
+
+func (ms methodSet) contains(methods methodSet) bool {
+	for name, sig := range methods {
+		if s, ok := ms[name]; !ok || !s.identical(sig) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// typeMethodSets returns method sets of every named type which has methods, or is a structure.
+func (p Parser) typeMethodSets() map[string]typeMethods {
+	declared := make(map[string]typeMethods)
+	for _, f := range p.functionsByName {
+		if f.Receiver.Type == "" {
+			continue
+		}
+
+		pkg := f.Receiver.Pkg
+		if f.ImportPath != "" {
+			pkg = f.ImportPath
+		}
+
+		id := pkg + "." + f.Receiver.Type
+		set, ok := declared[id]
+		if !ok {
+			set = typeMethods{value: make(methodSet), pointer: make(methodSet)}
+			declared[id] = set
+		}
+
+		sig := signature{text: signatureOf(pkg, p.importTable[f.Path], f.typeParamNames(), f.Parameters, f.Returns)}
+		if typed, ok := p.typedSignature(f); ok {
+			sig = typed
+		}
+		set.pointer[f.Name] = sig
+		if !f.Receiver.IsPointer {
+			set.value[f.Name] = sig
+		}
+	}
+
+	sets := make(map[string]typeMethods)
+	for id := range declared {
+		sets[id] = p.promotedMethods(id, declared, make(map[string]bool))
+	}
+	for id := range p.structureTypes {
+		sets[id] = p.promotedMethods(id, declared, make(map[string]bool))
+	}
+
+	return sets
+}
+
+// promotedMethods returns methods which are declared on the type, and promoted from its embedded fields.
+// Methods of shallower depth hide the same name of embedded fields.
+func (p Parser) promotedMethods(id string, declared map[string]typeMethods, visited map[string]bool) (set typeMethods) {
+	set = typeMethods{value: make(methodSet), pointer: make(methodSet)}
+	if visited[id] {
+		return
+	}
+	visited[id] = true
+
+	for name, sig := range declared[id].value {
+		set.value[name] = sig
+	}
+	for name, sig := range declared[id].pointer {
+		set.pointer[name] = sig
+	}
+
+	strct, ok := p.structureTypes[id]
+	if !ok {
+		return
+	}
+
+	pkg := strct.PkgName
+	if strct.ImportPath != "" {
+		pkg = strct.ImportPath
+	}
+
+	for _, field := range strct.Parameters {
+		if field.Name != "" {
+			continue
+		}
+
+		embedded := qualifyType(pkg, p.importTable[strct.File], field.Type)
+
+		// methods of embedded interface, or of embedded *T are promoted to both of S and *S
+		_, isStructure := p.structureTypes[embedded]
+		_, hasMethods := declared[embedded]
+		if !isStructure && !hasMethods {
+			if methods, ok := p.interfaceMethods(embedded, make(map[string]bool)); ok {
+				set.value.promote(methods)
+				set.pointer.promote(methods)
+			}
+			continue
+		}
+
+		promoted := p.promotedMethods(embedded, declared, visited)
+		if field.IsPointer {
+			set.value.promote(promoted.pointer)
+		} else {
+			set.value.promote(promoted.value)
+		}
+		set.pointer.promote(promoted.pointer)
+	}
+
+	return
+}
+
+func (ms methodSet) promote(methods methodSet) {
+	for name, sig := range methods {
+		if _, ok := ms[name]; !ok {
+			ms[name] = sig
+		}
+	}
+}
+
+// interfaceMethods returns methods of the interface, including methods of embedded interfaces.
+func (p Parser) interfaceMethods(id string, visited map[string]bool) (methods methodSet, ok bool) {
+	if visited[id] {
+		return make(methodSet), true
+	}
+	visited[id] = true
+
+	iface, ok := p.interfaceTypes[id]
+	if !ok {
+		return p.importedInterfaceMethods(id)
+	}
+
+	pkg := iface.PkgName
+	if iface.ImportPath != "" {
+		pkg = iface.ImportPath
+	}
+	imports := p.importTable[iface.File]
+
+	typed := p.typedInterfaceMethods(pkg, iface.Name)
+
+	methods = make(methodSet)
+	for _, method := range iface.Methods {
+		methods[method.Name] = signature{text: signatureOf(pkg, imports, iface.TypeParams.typeParamNames(), method.Parameters, method.Returns)}
+		if sig, ok := typed[method.Name]; ok {
+			methods[method.Name] = sig
+		}
+	}
+
+	for _, embedded := range iface.Embedded {
+		if embeddedMethods, ok := p.interfaceMethods(embedded, visited); ok {
+			methods.promote(embeddedMethods)
+		}
+	}
+
+	return methods, true
+}
+
+// importedInterfaceMethods loads the interface from source of its package, when it is not parsed.
+func (p Parser) importedInterfaceMethods(id string) (methods methodSet, ok bool) {
+	if id == "error" {
+		iface := types.Universe.Lookup("error").Type().Underlying().(*types.Interface)
+		return methodSet{"Error": typesSignature(iface.Method(0).Type().(*types.Signature))}, true
+	}
+
+	index := strings.LastIndex(id, ".")
+	if index < 0 {
+		return
+	}
+
+	// packages of the module are parsed already, so the type is not an interface
+	if _, parsed := p.symbolTables[id[:index]]; parsed {
+		return
+	}
+
+	imp := p.importer
+	if imp == nil {
+		imp = newSourceImporter()
+	}
+
+	pkg, err := imp.Import(id[:index])
+	if err != nil {
+		return
+	}
+
+	obj, isType := pkg.Scope().Lookup(id[index+1:]).(*types.TypeName)
+	if !isType {
+		return
+	}
+
+	iface, isInterface := obj.Type().Underlying().(*types.Interface)
+	if !isInterface {
+		return
+	}
+
+	methods = make(methodSet)
+	for i := 0; i < iface.NumMethods(); i++ {
+		methods[iface.Method(i).Name()] = typesSignature(iface.Method(i).Type().(*types.Signature))
+	}
+
+	return methods, true
+}
+
+// typedSignature returns signature of the method by the type checker, in typed mode.
+func (p Parser) typedSignature(f *FunctionStatement) (sig signature, ok bool) {
+	decl, isDecl := f.Node.(*ast.FuncDecl)
+	if p.typesInfo == nil || !isDecl {
+		return
+	}
+
+	fn, isFunc := p.typesInfo.Defs[decl.Name].(*types.Func)
+	if !isFunc {
+		return
+	}
+
+	return typesSignature(fn.Type().(*types.Signature)), true
+}
+
+// typedInterfaceMethods returns signatures of methods that are declared in the interface by the type checker,
+// in typed mode. pkg is import path of the interface.
+func (p Parser) typedInterfaceMethods(pkg, name string) (methods methodSet) {
+	typesPackage, ok := p.typesPackages[pkg]
+	if !ok || typesPackage == nil {
+		return
+	}
+
+	obj, isType := typesPackage.Scope().Lookup(name).(*types.TypeName)
+	if !isType {
+		return
+	}
+
+	iface, isInterface := obj.Type().Underlying().(*types.Interface)
+	if !isInterface {
+		return
+	}
+
+	methods = make(methodSet)
+	for i := 0; i < iface.NumExplicitMethods(); i++ {
+		methods[iface.ExplicitMethod(i).Name()] = typesSignature(iface.ExplicitMethod(i).Type().(*types.Signature))
+	}
+
+	return
+}
+
+// typesSignature returns signature by the type checker. Its text is the same form of signatureOf,
+// so it can be compared with signatures from syntax.
+func typesSignature(sig *types.Signature) signature {
+	list := func(tuple *types.Tuple, variadic bool) string {
+		ts := make([]string, 0, tuple.Len())
+		for _, t := range typesTyps(tuple, variadic) {
+			ts = append(ts, t.canonical().String())
+		}
+
+		return strings.Join(ts, ",")
+	}
+
+	return signature{
+		text:  "(" + list(sig.Params(), sig.Variadic()) + ")(" + list(sig.Results(), false) + ")",
+		typed: sig,
+	}
+}
+
+// signatureOf returns signature of parameters and returns, which types are qualified by import path.
+// Pointers and variadic parameters are kept, so *[]byte and ...byte are different from []byte.
+// Names of parameters are dropped, and byte, rune and any are written as the types they denote.
+// typeParams are names of type parameters in scope, which are kept unqualified.
+func signatureOf(pkg string, imports map[string]Import, typeParams map[string]bool, parameters, returns Parameters) string {
+	list := func(ps Parameters) string {
+		ts := make([]string, 0, len(ps))
+		for _, prm := range ps {
+			if prm.Typ != nil {
+				ts = append(ts, prm.Typ.qualify(pkg, imports, typeParams).canonical().String())
+				continue
+			}
+
+			typ := prm.Type
+			if !typeParams[typ] {
+				typ = qualifyType(pkg, imports, typ)
+			}
+			if prm.IsPointer {
+				typ = "*" + typ
+			}
+			ts = append(ts, typ)
+		}
+
+		return strings.Join(ts, ",")
+	}
+
+	return "(" + list(parameters) + ")(" + list(returns) + ")"
+}
+
+func newSourceImporter() types.Importer {
+	return importer.ForCompiler(token.NewFileSet(), "source", nil)
+}
@@ -0,0 +1,337 @@
+package analyzer
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParser_Implementations(t *testing.T) {
+	root := writeModule(t, map[string]string{
+		"go.mod": "module example.com/sample\n",
+		"main.go": `package main
+
+import (
+	"io"
+
+	"example.com/sample/util"
+)
+
+type Namer interface {
+	Name() string
+}
+
+type Runner interface {
+	Namer
+	Run(args ...string) error
+}
+
+type ReadNamer interface {
+	io.Reader
+	Namer
+}
+
+type Base struct{}
+
+func (b Base) Name() string {
+	return "base"
+}
+
+type Server struct {
+	Base
+}
+
+func (s *Server) Run(args ...string) error {
+	return nil
+}
+
+type Worker struct {
+	*util.Job
+}
+
+type File struct{}
+
+func (f File) Name() string {
+	return "file"
+}
+
+func (f File) Read(p []byte) (n int, err error) {
+	return 0, nil
+}
+
+type Name string
+
+func (n Name) Name() string {
+	return string(n)
+}
+`,
+		"util/util.go": `package util
+
+type Job struct{}
+
+func (j *Job) Name() string {
+	return "job"
+}
+
+func (j *Job) Run(args ...string) error {
+	return nil
+}
+`,
+	})
+
+	p := NewParser(root)
+	p.SetRecursive(true)
+	assert.NoError(t, p.Parse())
+
+	tests := []struct {
+		name  string
+		iface string
+		want  []Implementation
+	}{{
+		name:  "임베딩된 구조체의 메소드로 구현하는 경우",
+		iface: "example.com/sample.Namer",
+		want: []Implementation{
+			{Type: "example.com/sample.Base", Interface: "example.com/sample.Namer"},
+			{Type: "example.com/sample.File", Interface: "example.com/sample.Namer"},
+			{Type: "example.com/sample.Name", Interface: "example.com/sample.Namer"},
+			{Type: "example.com/sample.Server", Interface: "example.com/sample.Namer"},
+			{Type: "example.com/sample.Worker", Interface: "example.com/sample.Namer"},
+			{Type: "example.com/sample/util.Job", Interface: "example.com/sample.Namer", Pointer: true},
+		},
+	}, {
+		name:  "포인터 리시버 메소드가 있는 경우",
+		iface: "example.com/sample.Runner",
+		want: []Implementation{
+			{Type: "example.com/sample.Server", Interface: "example.com/sample.Runner", Pointer: true},
+			{Type: "example.com/sample.Worker", Interface: "example.com/sample.Runner"},
+			{Type: "example.com/sample/util.Job", Interface: "example.com/sample.Runner", Pointer: true},
+		},
+	}, {
+		name:  "외부 패키지의 인터페이스를 임베딩한 경우",
+		iface: "example.com/sample.ReadNamer",
+		want: []Implementation{
+			{Type: "example.com/sample.File", Interface: "example.com/sample.ReadNamer"},
+		},
+	}, {
+		name:  "외부 패키지의 인터페이스인 경우",
+		iface: "io.Reader",
+		want: []Implementation{
+			{Type: "example.com/sample.File", Interface: "io.Reader"},
+		},
+	}, {
+		name:  "알 수 없는 인터페이스인 경우",
+		iface: "example.com/sample.Unknown",
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, p.Implementations(tt.iface))
+		})
+	}
+}
+
+func TestParser_Implements(t *testing.T) {
+	root := writeModule(t, map[string]string{
+		"go.mod": "module example.com/sample\n",
+		"main.go": `package main
+
+type Namer interface {
+	Name() string
+}
+
+type Runner interface {
+	Run() error
+}
+
+type Server struct{}
+
+func (s Server) Name() string {
+	return ""
+}
+
+func (s *Server) Run() error {
+	return nil
+}
+`,
+	})
+
+	p := NewParser(root)
+	p.SetRecursive(true)
+	assert.NoError(t, p.Parse())
+
+	assert.Equal(t, []Implementation{
+		{Type: "example.com/sample.Server", Interface: "example.com/sample.Namer"},
+	}, p.Implements("example.com/sample.Server"))
+
+	assert.Equal(t, []Implementation{
+		{Type: "example.com/sample.Server", Interface: "example.com/sample.Namer"},
+		{Type: "example.com/sample.Server", Interface: "example.com/sample.Runner", Pointer: true},
+	}, p.Implements("*example.com/sample.Server"))
+}
+
+func TestParser_ImplementationsParseFile(t *testing.T) {
+	root := writeModule(t, map[string]string{
+		"go.mod": "module example.com/sample\n",
+	})
+
+	p := NewParser(filepath.Join(root, "main.go"))
+	assert.NoError(t, p.ParseFile(`package main
+
+type Namer interface {
+	Name() string
+}
+
+type Server struct{}
+
+func (s Server) Name() string {
+	return ""
+}
+
+func (s *Server) Run() {}
+`))
+
+	structures := p.Structures()
+	if assert.Len(t, structures, 1) {
+		assert.Len(t, structures[0].Methods(), 2)
+	}
+
+	assert.Equal(t, []Implementation{
+		{Type: "example.com/sample.Server", Interface: "example.com/sample.Namer"},
+	}, p.Implementations("example.com/sample.Namer"))
+
+	// parsing another file links methods again, without duplication
+	p.path = filepath.Join(root, "other.go")
+	assert.NoError(t, p.ParseFile("package main\n\nfunc other() {}\n"))
+
+	structures = p.Structures()
+	if assert.Len(t, structures, 1) {
+		assert.Len(t, structures[0].Methods(), 2)
+	}
+}
+
+func TestParser_ImplementationsSignature(t *testing.T) {
+	root := writeModule(t, map[string]string{
+		"go.mod": "module example.com/sample\n",
+		"main.go": `package main
+
+import "io"
+
+type Writer interface {
+	Write(p *[]byte) error
+}
+
+type Printer interface {
+	Print(args ...string)
+}
+
+type Copier interface {
+	Copy(w io.Writer, r *io.LimitedReader) (int64, error)
+}
+
+type Iterator interface {
+	Each(fn func(int) bool)
+}
+
+type Visitor interface {
+	Visit(v interface{}) error
+}
+
+type W struct{}
+
+func (w W) Write(p []byte) error {
+	return nil
+}
+
+type PW struct{}
+
+func (w PW) Write(p *[]byte) error {
+	return nil
+}
+
+type P struct{}
+
+func (p P) Print(args []string) {}
+
+type VP struct{}
+
+func (p VP) Print(args ...string) {}
+
+type C struct{}
+
+func (c C) Copy(w io.Writer, r io.LimitedReader) (int64, error) {
+	return 0, nil
+}
+
+type PC struct{}
+
+func (c PC) Copy(w io.Writer, r *io.LimitedReader) (n int64, err error) {
+	return 0, nil
+}
+
+type I struct{}
+
+func (i I) Each(fn func(x int) bool) {}
+
+type V struct{}
+
+func (v V) Visit(v2 any) error {
+	return nil
+}
+
+type RW struct{}
+
+func (rw RW) Read(p []byte) (int, error) {
+	return 0, nil
+}
+
+func (rw RW) Write(p []uint8) (n int, err error) {
+	return 0, nil
+}
+`,
+	})
+
+	tests := []struct {
+		name  string
+		iface string
+		want  []Implementation
+	}{{
+		name:  "포인터가 다른 경우",
+		iface: "example.com/sample.Writer",
+		want:  []Implementation{{Type: "example.com/sample.PW", Interface: "example.com/sample.Writer"}},
+	}, {
+		name:  "가변 인자와 슬라이스인 경우",
+		iface: "example.com/sample.Printer",
+		want:  []Implementation{{Type: "example.com/sample.VP", Interface: "example.com/sample.Printer"}},
+	}, {
+		name:  "다른 패키지 타입의 포인터가 다른 경우",
+		iface: "example.com/sample.Copier",
+		want:  []Implementation{{Type: "example.com/sample.PC", Interface: "example.com/sample.Copier"}},
+	}, {
+		name:  "함수 타입 파라미터의 이름이 다른 경우",
+		iface: "example.com/sample.Iterator",
+		want:  []Implementation{{Type: "example.com/sample.I", Interface: "example.com/sample.Iterator"}},
+	}, {
+		name:  "any와 interface{}인 경우",
+		iface: "example.com/sample.Visitor",
+		want:  []Implementation{{Type: "example.com/sample.V", Interface: "example.com/sample.Visitor"}},
+	}, {
+		name:  "byte와 uint8인 경우",
+		iface: "io.ReadWriter",
+		want:  []Implementation{{Type: "example.com/sample.RW", Interface: "io.ReadWriter"}},
+	}}
+
+	for _, typed := range []bool{false, true} {
+		p := NewParser(root)
+		p.SetRecursive(true)
+		p.SetTyped(typed)
+		assert.NoError(t, p.Parse())
+
+		for _, tt := range tests {
+			t.Run(fmt.Sprintf("%s typed=%v", tt.name, typed), func(t *testing.T) {
+				assert.Equal(t, tt.want, p.Implementations(tt.iface))
+			})
+		}
+	}
+}
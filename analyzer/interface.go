@@ -0,0 +1,111 @@
+package analyzer
+
+import (
+	"go/ast"
+	"go/token"
+	"sort"
+	"strings"
+)
+
+// Interface is a declared interface type.
+type Interface struct {
+	PkgName    string
+	ImportPath string
+	File       string
+	Pos        token.Pos
+	Name       string
+	TypeParams Parameters
+	Methods    []InterfaceMethod
+	Embedded   []string // identifiers of embedded interfaces, like io.Reader or example.com/sample.Closer
+}
+
+// InterfaceMethod is a method signature that is declared in an interface.
+type InterfaceMethod struct {
+	Name       string
+	Parameters Parameters
+	Returns    Parameters
+}
+
+func (m InterfaceMethod) String() string {
+	return m.Name + m.Parameters.String() + m.Returns.String()
+}
+
+func (i Interface) Identifier() string {
+	pkg := i.PkgName
+	if i.ImportPath != "" {
+		pkg = i.ImportPath
+	}
+
+	return pkg + "." + i.Name
+}
+
+// String returns interface literal of i, like "interface {Read(p []byte)(n int, err error)}".
+func (i Interface) String() string {
+	elements := make([]string, 0, len(i.Embedded)+len(i.Methods))
+	elements = append(elements, i.Embedded...)
+	for _, method := range i.Methods {
+		elements = append(elements, method.String())
+	}
+
+	return "interface {" + strings.Join(elements, "; ") + "}"
+}
+
+// Interfaces returns declared interfaces, sorted by identifier.
+func (p Parser) Interfaces() []Interface {
+	is := make([]Interface, 0, len(p.interfaceTypes))
+	for _, i := range p.interfaceTypes {
+		is = append(is, i)
+	}
+
+	sort.Slice(is, func(a, b int) bool {
+		return is[a].Identifier() < is[b].Identifier()
+	})
+
+	return is
+}
+
+func (p Parser) Interface(name string) (i Interface, ok bool) {
+	i, ok = p.interfaceTypes[name]
+	return
+}
+
+// ParseInterface parses methods and embedded interfaces of x. Embedded interfaces are returned as they are written,
+// so the caller qualifies them with imports of the file.
+func (p *Parser) ParseInterface(pkgName, name string, x *ast.InterfaceType) (i Interface) {
+	i.PkgName = pkgName
+	i.Name = name
+	i.Methods = make([]InterfaceMethod, 0)
+	i.Embedded = make([]string, 0)
+
+	if x.Methods == nil {
+		return
+	}
+
+	for _, field := range x.Methods.List {
+		if len(field.Names) == 0 {
+			switch field.Type.(type) {
+			case *ast.Ident, *ast.SelectorExpr:
+				i.Embedded = append(i.Embedded, p.ParseType(pkgName, field.Type).String())
+			default:
+				p.report(Info, field.Type, "type set of constraint interface is not analyzed")
+			}
+			continue
+		}
+
+		typ, ok := field.Type.(*ast.FuncType)
+		if !ok {
+			continue
+		}
+
+		parameters, returns := p.ParseFuncType(pkgName, typ)
+		for _, methodName := range field.Names {
+			i.Methods = append(i.Methods, InterfaceMethod{
+				Name:       methodName.Name,
+				Parameters: parameters,
+				Returns:    returns,
+			})
+		}
+	}
+
+	return
+}
@@ -0,0 +1,91 @@
+package analyzer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParser_Interfaces(t *testing.T) {
+	root := writeModule(t, map[string]string{
+		"go.mod": "module example.com/sample\n",
+		"main.go": `package main
+
+import (
+	"io"
+
+	"example.com/sample/util"
+)
+
+type Closer interface {
+	Close() error
+}
+
+type ReadCloser interface {
+	io.Reader
+	Closer
+	util.Namer
+
+	ReadAt(p []byte, off int64) (n int, err error)
+	Reset()
+}
+
+func main() {
+	var x interface{ Name() string }
+	_ = x
+}
+`,
+		"util/util.go": `package util
+
+type Namer interface {
+	Name() string
+}
+`,
+	})
+
+	p := NewParser(root)
+	p.SetRecursive(true)
+	assert.NoError(t, p.Parse())
+
+	interfaces := p.Interfaces()
+	if assert.Len(t, interfaces, 3) {
+		assert.Equal(t, "example.com/sample.Closer", interfaces[0].Identifier())
+		assert.Equal(t, "example.com/sample.ReadCloser", interfaces[1].Identifier())
+		assert.Equal(t, "example.com/sample/util.Namer", interfaces[2].Identifier())
+	}
+
+	i, ok := p.Interface("example.com/sample.ReadCloser")
+	if !assert.True(t, ok) {
+		return
+	}
+
+	assert.Equal(t, "main", i.PkgName)
+	assert.Equal(t, "ReadCloser", i.Name)
+	assert.Equal(t, []string{"io.Reader", "example.com/sample.Closer", "example.com/sample/util.Namer"}, i.Embedded)
+	if assert.Len(t, i.Methods, 2) {
+		assert.Equal(t, "ReadAt", i.Methods[0].Name)
+		assert.Equal(t, Parameters{
+			{Pkg: "main", Name: "p", Type: "[]byte", Typ: &Typ{Kind: SliceType, Elem: named("byte")}},
+			{Pkg: "main", Name: "off", Type: "int64", Typ: named("int64")},
+		}, i.Methods[0].Parameters)
+		assert.Equal(t, Parameters{
+			{Pkg: "main", Name: "n", Type: "int", Typ: named("int")},
+			{Pkg: "main", Name: "err", Type: "error", Typ: named("error")},
+		}, i.Methods[0].Returns)
+
+		assert.Equal(t, "Reset", i.Methods[1].Name)
+		assert.Empty(t, i.Methods[1].Parameters)
+	}
+}
+
+func TestInterface_String(t *testing.T) {
+	i := Interface{
+		Embedded: []string{"io.Reader"},
+		Methods: []InterfaceMethod{{
+			Name:    "Close",
+			Returns: Parameters{{Type: "error"}},
+		}},
+	}
+
+	assert.Equal(t, "interface {io.Reader; Close()(error)}", i.String())
+}
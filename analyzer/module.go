@@ -0,0 +1,309 @@
+package analyzer
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"go/ast"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+const goModFileName = "go.mod"
+
+var errNoModuleDirective = errors.New("no module directive in go.mod")
+
+// findModuleRoot returns nearest directory which contains go.mod, starting from dir and going up.
+func findModuleRoot(dir string) (root string, ok bool) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", false
+	}
+
+	for {
+		if info, err := os.Stat(filepath.Join(dir, goModFileName)); err == nil && !info.IsDir() {
+			return dir, true
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", false
+		}
+		dir = parent
+	}
+}
+
+// readModulePath reads module path from module directive of go.mod file.
+func readModulePath(goModPath string) (modulePath string, err error) {
+	file, err := os.Open(goModPath)
+	if err != nil {
+		return
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if index := strings.Index(line, "//"); index >= 0 {
+			line = line[:index]
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) != 2 || fields[0] != "module" {
+			continue
+		}
+
+		modulePath = fields[1]
+		if unquoted, err := strconv.Unquote(modulePath); err == nil {
+			modulePath = unquoted
+		}
+		return
+	}
+
+	if err = scanner.Err(); err != nil {
+		return
+	}
+
+	return "", errNoModuleDirective
+}
+
+// importPathOf returns import path of dir, which is placed under module root.
+func importPathOf(modulePath, root, dir string) (importPath string, err error) {
+	rel, err := filepath.Rel(root, dir)
+	if err != nil {
+		return
+	}
+
+	if rel == "." {
+		return modulePath, nil
+	}
+
+	return modulePath + "/" + filepath.ToSlash(rel), nil
+}
+
+// skipDir reports whether go tool ignores the directory while matching "./..." pattern.
+func skipDir(root, dir string, name string) bool {
+	if dir == root {
+		return false
+	}
+
+	if strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_") || name == "testdata" || name == "vendor" {
+		return true
+	}
+
+	// nested module is not a part of this module
+	if _, err := os.Stat(filepath.Join(dir, goModFileName)); err == nil {
+		return true
+	}
+
+	return false
+}
+
+func (p *Parser) parseModule(ctx context.Context) (err error) {
+	path, err := filepath.Abs(p.path)
+	if err != nil {
+		return
+	}
+
+	root, ok := findModuleRoot(path)
+	if !ok {
+		return fmt.Errorf("can not find %s from %s", goModFileName, p.path)
+	}
+
+	p.modulePath, err = readModulePath(filepath.Join(root, goModFileName))
+	if err != nil {
+		return
+	}
+
+	// every package of the module is parsed, so calls from path into other packages of the module are resolved.
+	// Results out of path are dropped after linking
+	units := make([]parseUnit, 0)
+	err = filepath.WalkDir(root, func(dir string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		if !d.IsDir() {
+			return nil
+		}
+
+		if skipDir(root, dir, d.Name()) {
+			return filepath.SkipDir
+		}
+
+		importPath, err := importPathOf(p.modulePath, root, dir)
+		if err != nil {
+			return err
+		}
+
+		units = append(units, parseUnit{dir: dir, importPath: importPath})
+		return nil
+	})
+
+	if err != nil {
+		return
+	}
+
+	if err = p.parseUnits(ctx, units); err != nil {
+		return
+	}
+
+	if err = p.checkTypes(ctx); err != nil {
+		return
+	}
+
+	p.link()
+	if path != root {
+		p.keepUnder(path)
+	}
+	return
+}
+
+// keepUnder drops functions, calls, structures, interfaces and implementations which are not declared in files
+// under path. Packages out of path are parsed only to resolve calls into them, so calls from path keep their
+// declarations, but calls from packages out of path are dropped.
+func (p *Parser) keepUnder(path string) {
+	under := func(file string) bool {
+		return file == path || strings.HasPrefix(file, path+string(filepath.Separator))
+	}
+
+	calls := make([]FunctionCall, 0, len(p.functionCalls))
+	for _, call := range p.functionCalls {
+		if under(call.File) {
+			calls = append(calls, call)
+		}
+	}
+	p.functionCalls = calls
+
+	declared := make(map[string]bool) // identifiers of types that are declared under path
+	for identifier, f := range p.functionsByName {
+		if !under(f.Path) {
+			delete(p.functionsByName, identifier)
+			continue
+		}
+
+		fCalls := make([]FunctionCall, 0, len(f.Calls))
+		for _, call := range f.Calls {
+			if under(call.File) {
+				fCalls = append(fCalls, call)
+			}
+		}
+		f.Calls = fCalls
+	}
+
+	for identifier, strct := range p.structureTypes {
+		if under(strct.File) {
+			declared[identifier] = true
+		} else {
+			delete(p.structureTypes, identifier)
+		}
+	}
+
+	for identifier, iface := range p.interfaceTypes {
+		if under(iface.File) {
+			declared[identifier] = true
+		} else {
+			delete(p.interfaceTypes, identifier)
+		}
+	}
+
+	implementations := make([]Implementation, 0, len(p.implementations))
+	for _, implementation := range p.implementations {
+		if declared[implementation.Type] || declared[implementation.Interface] {
+			implementations = append(implementations, implementation)
+		}
+	}
+	p.implementations = implementations
+
+	for file := range p.importTable {
+		if !under(file) {
+			delete(p.importTable, file)
+		}
+	}
+
+	diagnostics := make(Diagnostics, 0, len(p.diagnostics))
+	for _, d := range p.diagnostics {
+		if under(d.Position.Filename) {
+			diagnostics = append(diagnostics, d)
+		}
+	}
+	p.diagnostics = diagnostics
+
+	errs := make([]FileError, 0, len(p.errors))
+	for _, fileErr := range p.errors {
+		if under(fileErr.File) {
+			errs = append(errs, fileErr)
+		}
+	}
+	p.errors = errs
+}
+
+// lookupImportPath returns import path of dir, if dir is placed in a module.
+// It returns empty string when there is no go.mod, then package name is used as identity instead.
+func (p *Parser) lookupImportPath(dir string) string {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return ""
+	}
+
+	root, ok := findModuleRoot(dir)
+	if !ok {
+		return ""
+	}
+
+	modulePath, err := readModulePath(filepath.Join(root, goModFileName))
+	if err != nil {
+		return ""
+	}
+	p.modulePath = modulePath
+
+	importPath, err := importPathOf(modulePath, root, dir)
+	if err != nil {
+		return ""
+	}
+
+	return importPath
+}
+
+// calleeIdentifier returns identifier of function that fun refers, which can be matched with FunctionStatement.Identifier.
+// imports is import table of the file that fun is written in.
+// It returns empty string, when callee can not be decided by syntax.
+// scope is where fun is written. Names of variables in scope, like f of f := func() {}, are not functions of the package.
+func calleeIdentifier(pkgName, importPath string, imports map[string]Import, scope *SymbolTable, fun ast.Expr) string {
+	pkg := pkgName
+	if importPath != "" {
+		pkg = importPath
+	}
+
+	switch x := fun.(type) {
+	case *ast.Ident:
+		if _, ok := scope.Lookup(x.Name); ok {
+			return ""
+		}
+
+		return pkg + "." + x.Name
+	case *ast.SelectorExpr:
+		ident, ok := x.X.(*ast.Ident)
+		if !ok || ident.Obj != nil {
+			return ""
+		}
+
+		if _, ok := scope.Lookup(ident.Name); ok {
+			return ""
+		}
+
+		if imp, ok := imports[ident.Name]; ok {
+			return imp.Path + "." + x.Sel.Name
+		}
+	}
+
+	return ""
+}
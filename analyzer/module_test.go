@@ -0,0 +1,449 @@
+package analyzer
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func writeModule(t *testing.T, files map[string]string) (root string) {
+	root = t.TempDir()
+
+	for name, source := range files {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := os.WriteFile(path, []byte(source), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return
+}
+
+func TestReadModulePath(t *testing.T) {
+	tests := []struct {
+		name   string
+		goMod  string
+		want   string
+		hasErr bool
+	}{{
+		name:  "module directive만 있는 경우",
+		goMod: "module example.com/sample\n",
+		want:  "example.com/sample",
+	}, {
+		name:  "따옴표와 주석이 있는 경우",
+		goMod: "// comment\nmodule \"example.com/sample\" // comment\n\ngo 1.17\n",
+		want:  "example.com/sample",
+	}, {
+		name:   "module directive가 없는 경우",
+		goMod:  "go 1.17\n",
+		hasErr: true,
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := writeModule(t, map[string]string{"go.mod": tt.goMod})
+
+			got, err := readModulePath(filepath.Join(root, "go.mod"))
+			if tt.hasErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestParser_ParseRecursive(t *testing.T) {
+	root := writeModule(t, map[string]string{
+		"go.mod": "module example.com/sample\n\ngo 1.17\n",
+		"main.go": `package main
+
+import "example.com/sample/util"
+
+func main() {
+	util.Run()
+	helper()
+}
+
+func helper() {}
+`,
+		"util/util.go": `package util
+
+type Server struct {
+	Name string
+}
+
+func (s Server) Start() {}
+
+func Run() {}
+`,
+		"context/context.go": `package context
+
+func Run() {}
+`,
+		"testdata/ignored.go": `package ignored
+
+func Ignored() {}
+`,
+		"nested/go.mod": "module example.com/nested\n",
+		"nested/nested.go": `package nested
+
+func Nested() {}
+`,
+	})
+
+	p := NewParser(root)
+	p.SetRecursive(true)
+	assert.NoError(t, p.Parse())
+
+	assert.Equal(t, "example.com/sample", p.ModulePath())
+
+	for _, name := range []string{"example.com/sample.main", "example.com/sample.helper", "example.com/sample/util.Run", "example.com/sample/util.Server.Start", "example.com/sample/context.Run"} {
+		_, ok := p.Function(name)
+		assert.True(t, ok, name)
+	}
+
+	for _, name := range []string{"example.com/sample/testdata.Ignored", "example.com/nested.Nested"} {
+		_, ok := p.Function(name)
+		assert.False(t, ok, name)
+	}
+
+	run, _ := p.Function("example.com/sample/util.Run")
+	if assert.Len(t, run.Calls, 1) {
+		assert.Equal(t, "main", run.Calls[0].Parent.Name)
+	}
+
+	helper, _ := p.Function("example.com/sample.helper")
+	assert.Len(t, helper.Calls, 1)
+
+	structures := p.Structures()
+	if assert.Len(t, structures, 1) {
+		assert.Equal(t, "example.com/sample/util.Server", structures[0].Identifier())
+		assert.Len(t, structures[0].Methods(), 1)
+	}
+}
+
+func TestParser_ParseRecursiveSubdirectory(t *testing.T) {
+	root := writeModule(t, map[string]string{
+		"go.mod": "module example.com/sample\n",
+		"api/api.go": `package api
+
+import "example.com/sample/store"
+
+type Handler struct{}
+
+func (h Handler) Serve() {
+	s := store.Open()
+	s.Get()
+}
+`,
+		"store/store.go": `package store
+
+type Store struct{}
+
+func Open() *Store {
+	return &Store{}
+}
+
+func (s *Store) Get() {}
+
+func unused() {
+	Open()
+}
+`,
+	})
+
+	p := NewParser(filepath.Join(root, "api"))
+	p.SetRecursive(true)
+	assert.NoError(t, p.Parse())
+
+	// calls into the sibling package are resolved, though only the subdirectory is reported
+	callees := make(map[string]bool)
+	for _, call := range p.FuncCalls() {
+		assert.Equal(t, "example.com/sample/api.Handler.Serve", call.Parent.Identifier())
+		callees[call.Identifier()] = call.FunctionDeclaration != nil
+	}
+	assert.Equal(t, map[string]bool{
+		"example.com/sample/store.Open":      true,
+		"example.com/sample/store.Store.Get": true,
+	}, callees)
+
+	functions := make([]string, 0)
+	for _, f := range p.Functions() {
+		functions = append(functions, f.Identifier())
+	}
+	assert.Equal(t, []string{"example.com/sample/api.Handler.Serve"}, functions)
+
+	structures := p.Structures()
+	if assert.Len(t, structures, 1) {
+		assert.Equal(t, "example.com/sample/api.Handler", structures[0].Identifier())
+	}
+}
+
+func TestParser_ParseSamePackageName(t *testing.T) {
+	root := writeModule(t, map[string]string{
+		"go.mod": "module example.com/sample\n",
+		"main.go": `package main
+
+import (
+	"example.com/sample/a/util"
+	butil "example.com/sample/b/util"
+)
+
+func main() {
+	util.Run()
+	butil.Run()
+	butil.Run()
+}
+`,
+		"a/util/util.go": `package util
+
+type Config struct{}
+
+func Run() {}
+`,
+		"b/util/util.go": `package util
+
+type Config struct{}
+
+func Run() {}
+`,
+	})
+
+	p := NewParser(root)
+	p.SetRecursive(true)
+	assert.NoError(t, p.Parse())
+
+	a, ok := p.Function("example.com/sample/a/util.Run")
+	assert.True(t, ok)
+	assert.Len(t, a.Calls, 1)
+
+	b, ok := p.Function("example.com/sample/b/util.Run")
+	assert.True(t, ok)
+	assert.Len(t, b.Calls, 2)
+
+	assert.Len(t, p.Structures(), 2)
+
+	imports := p.Imports(filepath.Join(root, "main.go"))
+	assert.Equal(t, "example.com/sample/b/util", imports["butil"].Path)
+}
+
+func TestParser_ParseIdentifier(t *testing.T) {
+	root := writeModule(t, map[string]string{
+		"go.mod": "module example.com/sample\n",
+		"util/util.go": `package util
+
+type Server struct{}
+
+func (s *Server) Start() {}
+
+func NewServer() *Server {
+	return &Server{}
+}
+`,
+	})
+
+	p := NewParser(filepath.Join(root, "util"))
+	assert.NoError(t, p.Parse())
+
+	_, ok := p.Function("example.com/sample/util.NewServer")
+	assert.True(t, ok)
+
+	_, ok = p.Function("example.com/sample/util.Server.Start")
+	assert.True(t, ok)
+
+	_, ok = p.Function("util.NewServer")
+	assert.False(t, ok)
+}
+
+func TestParser_ParseConcurrently(t *testing.T) {
+	roots := []string{
+		writeModule(t, map[string]string{
+			"go.mod": "module example.com/first\n",
+			"main.go": `package main
+
+func main() {
+	first()
+}
+
+func first() {}
+`,
+		}),
+		writeModule(t, map[string]string{
+			"go.mod": "module example.com/second\n",
+			"main.go": `package main
+
+func main() {
+	f := func() {
+		second()
+	}
+	f()
+}
+
+func second() {
+	third()
+}
+
+func third() {}
+`,
+		}),
+	}
+
+	const parsersPerModule = 4
+
+	parsers := make([]Parser, len(roots)*parsersPerModule)
+	errs := make([]error, len(parsers))
+
+	var wg sync.WaitGroup
+	for index := range parsers {
+		wg.Add(1)
+		go func(index int) {
+			defer wg.Done()
+
+			parsers[index] = NewParser(roots[index%len(roots)])
+			parsers[index].SetRecursive(true)
+			errs[index] = parsers[index].Parse()
+		}(index)
+	}
+	wg.Wait()
+
+	for index, p := range parsers {
+		assert.NoError(t, errs[index])
+
+		if index%len(roots) == 0 {
+			first, ok := p.Function("example.com/first.first")
+			if assert.True(t, ok) && assert.Len(t, first.Calls, 1) {
+				assert.Equal(t, "main", first.Calls[0].Parent.Name)
+			}
+			continue
+		}
+
+		second, ok := p.Function("example.com/second.second")
+		if assert.True(t, ok) && assert.Len(t, second.Calls, 1) {
+			assert.Equal(t, "main", second.Calls[0].Parent.Name)
+		}
+
+		third, ok := p.Function("example.com/second.third")
+		if assert.True(t, ok) && assert.Len(t, third.Calls, 1) {
+			assert.Equal(t, "second", third.Calls[0].Parent.Name)
+		}
+	}
+}
+
+func TestParser_ParseInits(t *testing.T) {
+	root := writeModule(t, map[string]string{
+		"go.mod": "module example.com/sample\n",
+		"a.go": `package main
+
+func init() {
+	first()
+}
+
+func first() {}
+`,
+		"b.go": `package main
+
+func init() {
+	second()
+}
+
+func init() {}
+
+func second() {}
+
+func main() {}
+`,
+	})
+
+	p := NewParser(root)
+	p.SetRecursive(true)
+	assert.NoError(t, p.Parse())
+
+	tests := []struct {
+		identifier string
+		callee     string
+	}{
+		{identifier: "example.com/sample.init#1", callee: "example.com/sample.first"},
+		{identifier: "example.com/sample.init#2", callee: "example.com/sample.second"},
+		{identifier: "example.com/sample.init#3"},
+	}
+
+	for _, tt := range tests {
+		init, ok := p.Function(tt.identifier)
+		if !assert.True(t, ok, tt.identifier) {
+			continue
+		}
+		assert.Equal(t, "init", init.Name)
+
+		if tt.callee == "" {
+			continue
+		}
+
+		callee, ok := p.Function(tt.callee)
+		if assert.True(t, ok) && assert.Len(t, callee.Calls, 1) {
+			assert.Equal(t, tt.identifier, callee.Calls[0].Parent.Identifier())
+		}
+	}
+
+	_, ok := p.Function("example.com/sample.init")
+	assert.False(t, ok)
+}
+
+func TestParser_ParseFuncValueCall(t *testing.T) {
+	root := writeModule(t, map[string]string{
+		"go.mod": "module example.com/m\n",
+		"app/app.go": `package app
+
+var handler = func() {}
+
+func f() {}
+
+func run(fn func()) {
+	fn()
+}
+
+func main() {
+	f := func() {}
+	f()
+	handler()
+	run(f)
+}
+`,
+	})
+
+	p := NewParser(root)
+	p.SetRecursive(true)
+	assert.NoError(t, p.Parse())
+
+	type call struct {
+		Identifier  string
+		IsFuncValue bool
+		Resolved    bool
+	}
+
+	calls := make([]call, 0)
+	for _, fc := range p.FuncCalls() {
+		calls = append(calls, call{fc.Identifier(), fc.IsFuncValue, fc.FunctionDeclaration != nil})
+	}
+	assert.Equal(t, []call{
+		{Identifier: "fn", IsFuncValue: true},
+		{Identifier: "f", IsFuncValue: true},
+		{Identifier: "handler", IsFuncValue: true},
+		{Identifier: "example.com/m/app.run", Resolved: true},
+	}, calls)
+
+	// f of main is a variable, not the function f
+	f, ok := p.Function("example.com/m/app.f")
+	if assert.True(t, ok) {
+		assert.Empty(t, f.Calls)
+	}
+}
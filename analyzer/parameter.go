@@ -2,16 +2,23 @@ package analyzer
 
 import (
 	"fmt"
+	"go/types"
 	"strings"
 )
 
 type Parameter struct {
-	Pkg                  string
-	Name                 string
-	IsPointer            bool
-	Type                 string
-	IsMultipleParameters bool
-	IsArgument           bool
+	Pkg                  string `json:"pkg,omitempty"`
+	Name                 string `json:"name,omitempty"`
+	IsPointer            bool   `json:"is_pointer,omitempty"`
+	Type                 string `json:"type"`
+	IsMultipleParameters bool   `json:"is_multiple_parameters,omitempty"`
+	IsArgument           bool   `json:"is_argument,omitempty"`
+	IsVariadic           bool   `json:"is_variadic,omitempty"` // Type has "..." prefix, like ...string
+	Typ                  *Typ   `json:"typ,omitempty"`         // structured type, including pointer. It is nil for arguments
+
+	// Object and TypeInfo are set in typed mode.
+	Object   types.Object `json:"-"`
+	TypeInfo types.Type   `json:"-"`
 }
 
 func (p Parameter) String() string {
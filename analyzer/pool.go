@@ -0,0 +1,126 @@
+package analyzer
+
+import (
+	"context"
+	"runtime"
+	"sync"
+)
+
+// parseUnit is a directory that is parsed by a worker.
+type parseUnit struct {
+	dir        string
+	importPath string
+}
+
+// SetParallelism sets number of workers which parse and inspect packages concurrently.
+// When n is less than 1, number of CPUs is used. Default is 1, which parses packages one by one.
+func (p *Parser) SetParallelism(n int) {
+	if n < 1 {
+		n = runtime.NumCPU()
+	}
+
+	p.parallelism = n
+}
+
+// parseUnits parses units with a bounded number of workers. Every unit is parsed into its own parser,
+// and merged in the order of units, so the result is same with parsing one by one.
+// Units which are not started yet are skipped when ctx is done.
+func (p *Parser) parseUnits(ctx context.Context, units []parseUnit) error {
+	results := make([]*Parser, len(units))
+	errs := make([]error, len(units))
+
+	workers := p.parallelism
+	if workers < 1 {
+		workers = 1
+	}
+	if workers > len(units) {
+		workers = len(units)
+	}
+
+	p.progress.start(len(units))
+
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for index := range jobs {
+				if errs[index] = ctx.Err(); errs[index] != nil {
+					continue
+				}
+
+				unit := units[index]
+				results[index] = p.fork()
+				errs[index] = results[index].parseDir(ctx, unit.dir, unit.importPath)
+			}
+		}()
+	}
+
+	for index := range units {
+		jobs <- index
+	}
+	close(jobs)
+	wg.Wait()
+
+	for index := range units {
+		if errs[index] != nil {
+			return errs[index]
+		}
+
+		p.merge(results[index])
+	}
+
+	return nil
+}
+
+// fork returns an empty parser which has same settings with p. Parsers share the file set, which is safe for concurrent use.
+func (p *Parser) fork() *Parser {
+	child := NewParser(p.path)
+	child.fset = p.fset
+	child.filter = p.filter
+	child.mode = p.mode
+	child.lenient = p.lenient
+	child.modulePath = p.modulePath
+	child.inspector = p.inspector
+	child.progress = p.progress
+	child.typed = p.typed
+
+	return &child
+}
+
+func (p *Parser) merge(child *Parser) {
+	for identifier, function := range child.functionsByName {
+		p.functionsByName[identifier] = function
+	}
+
+	p.functionCalls = append(p.functionCalls, child.functionCalls...)
+
+	for file, imports := range child.importTable {
+		p.importTable[file] = imports
+	}
+
+	for identifier, strct := range child.structureTypes {
+		p.structureTypes[identifier] = strct
+	}
+
+	for identifier, i := range child.interfaceTypes {
+		p.interfaceTypes[identifier] = i
+	}
+
+	for pkg, st := range child.symbolTables {
+		p.symbolTables[pkg] = st
+	}
+
+	for pkg, files := range child.syntax {
+		p.syntax[pkg] = files
+	}
+
+	for file, source := range child.sources {
+		p.sources[file] = source
+	}
+
+	p.errors = append(p.errors, child.errors...)
+	p.diagnostics = append(p.diagnostics, child.diagnostics...)
+}
@@ -0,0 +1,86 @@
+package analyzer
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParser_ParseParallel(t *testing.T) {
+	files := map[string]string{
+		"go.mod": "module example.com/sample\n",
+		"main.go": `package main
+
+import (
+	"example.com/sample/pkg0"
+	"example.com/sample/pkg1"
+)
+
+func main() {
+	pkg0.Run()
+	pkg1.Run()
+}
+`,
+	}
+
+	for i := 0; i < 16; i++ {
+		files[fmt.Sprintf("pkg%d/pkg.go", i)] = fmt.Sprintf(`package pkg%d
+
+type Server struct {
+	Name string
+}
+
+func (s *Server) Start() {
+	Run()
+}
+
+func Run() {
+	s := &Server{}
+	s.Start()
+	helper()
+}
+`, i)
+		files[fmt.Sprintf("pkg%d/helper.go", i)] = fmt.Sprintf(`package pkg%d
+
+func init() {}
+
+func helper() {
+	_ = [2]int{}
+}
+`, i)
+		files[fmt.Sprintf("pkg%d/pkg_test.go", i)] = fmt.Sprintf(`package pkg%d_test
+
+import "example.com/sample/pkg%d"
+
+func test() {
+	pkg%d.Run()
+}
+`, i, i, i)
+	}
+	root := writeModule(t, files)
+
+	sequential := NewParser(root)
+	sequential.SetRecursive(true)
+	assert.NoError(t, sequential.Parse())
+
+	for _, parallelism := range []int{0, 2, 8} {
+		t.Run(fmt.Sprintf("parallelism %d", parallelism), func(t *testing.T) {
+			parallel := NewParser(root)
+			parallel.SetRecursive(true)
+			parallel.SetParallelism(parallelism)
+			assert.NoError(t, parallel.Parse())
+
+			assert.Equal(t, sequential.Snapshot(), parallel.Snapshot())
+
+			callees := func(p Parser) (identifiers []string) {
+				for _, fc := range p.FuncCalls() {
+					identifiers = append(identifiers, fc.Identifier())
+				}
+				return
+			}
+			assert.Equal(t, callees(sequential), callees(parallel))
+			assert.Equal(t, len(sequential.Diagnostics()), len(parallel.Diagnostics()))
+		})
+	}
+}
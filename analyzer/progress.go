@@ -0,0 +1,90 @@
+package analyzer
+
+import "sync"
+
+// ProgressKind is kind of a progress event.
+type ProgressKind int
+
+const (
+	// PackageStarted is reported when a directory starts to be parsed.
+	PackageStarted ProgressKind = iota
+	// FileDone is reported when a file is inspected.
+	FileDone
+	// PackageDone is reported when every file of a directory is inspected.
+	PackageDone
+)
+
+func (k ProgressKind) String() string {
+	switch k {
+	case PackageStarted:
+		return "package started"
+	case FileDone:
+		return "file done"
+	case PackageDone:
+		return "package done"
+	}
+
+	return "unknown"
+}
+
+// Progress is an event of parsing. Package is import path of the directory, or the directory itself when it is out of a module.
+// Files counts files in the directory, and Packages counts directories to parse.
+type Progress struct {
+	Kind          ProgressKind
+	Package       string
+	File          string
+	Files         int
+	TotalFiles    int
+	Packages      int
+	TotalPackages int
+}
+
+// ProgressFunc receives progress events. Events are reported one by one, even if packages are parsed concurrently.
+type ProgressFunc func(progress Progress)
+
+// progressReporter is shared by forked parsers, so it counts packages of every worker.
+type progressReporter struct {
+	mu            sync.Mutex
+	fn            ProgressFunc
+	packages      int
+	totalPackages int
+}
+
+// SetProgress sets fn to receive progress of Parse and ParseFile. nil disables reporting.
+func (p *Parser) SetProgress(fn ProgressFunc) {
+	if fn == nil {
+		p.progress = nil
+		return
+	}
+
+	p.progress = &progressReporter{fn: fn}
+}
+
+func (r *progressReporter) start(total int) {
+	if r == nil {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.packages = 0
+	r.totalPackages = total
+}
+
+func (r *progressReporter) report(progress Progress) {
+	if r == nil {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if progress.Kind == PackageDone {
+		r.packages++
+	}
+	progress.Packages = r.packages
+	progress.TotalPackages = r.totalPackages
+
+	r.fn(progress)
+}
@@ -0,0 +1,138 @@
+package analyzer
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParser_ParseContext(t *testing.T) {
+	root := writeModule(t, map[string]string{
+		"go.mod": "module example.com/sample\n",
+		"main.go": `package main
+
+func main() {}
+`,
+		"util/util.go": `package util
+
+func Run() {}
+`,
+	})
+
+	t.Run("취소된 컨텍스트로 파싱하는 경우", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		p := NewParser(root)
+		p.SetRecursive(true)
+		assert.ErrorIs(t, p.ParseContext(ctx), context.Canceled)
+
+		p = NewParser(root)
+		assert.ErrorIs(t, p.ParseContext(ctx), context.Canceled)
+
+		p = NewParser(filepath.Join(root, "main.go"))
+		assert.ErrorIs(t, p.ParseFileContext(ctx, "package main\n"), context.Canceled)
+		assert.Empty(t, p.Functions())
+	})
+
+	t.Run("시간이 초과된 경우", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), -time.Second)
+		defer cancel()
+
+		p := NewParser(root)
+		p.SetRecursive(true)
+		p.SetParallelism(2)
+		assert.ErrorIs(t, p.ParseContext(ctx), context.DeadlineExceeded)
+	})
+
+	t.Run("파싱 중에 취소되는 경우", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		p := NewParser(root)
+		p.SetRecursive(true)
+		p.SetProgress(func(progress Progress) {
+			if progress.Kind == FileDone {
+				cancel()
+			}
+		})
+		assert.ErrorIs(t, p.ParseContext(ctx), context.Canceled)
+	})
+}
+
+func TestParser_SetProgress(t *testing.T) {
+	root := writeModule(t, map[string]string{
+		"go.mod": "module example.com/sample\n",
+		"main.go": `package main
+
+func main() {}
+`,
+		"util/a.go": `package util
+
+func A() {}
+`,
+		"util/b.go": `package util
+
+func B() {}
+`,
+	})
+
+	events := make([]Progress, 0)
+	p := NewParser(root)
+	p.SetRecursive(true)
+	p.SetProgress(func(progress Progress) {
+		events = append(events, progress)
+	})
+	assert.NoError(t, p.Parse())
+
+	assert.Equal(t, []Progress{{
+		Kind:          PackageStarted,
+		Package:       "example.com/sample",
+		TotalPackages: 2,
+	}, {
+		Kind:          FileDone,
+		Package:       "example.com/sample",
+		File:          filepath.Join(root, "main.go"),
+		Files:         1,
+		TotalFiles:    1,
+		TotalPackages: 2,
+	}, {
+		Kind:          PackageDone,
+		Package:       "example.com/sample",
+		Files:         1,
+		TotalFiles:    1,
+		Packages:      1,
+		TotalPackages: 2,
+	}, {
+		Kind:          PackageStarted,
+		Package:       "example.com/sample/util",
+		Packages:      1,
+		TotalPackages: 2,
+	}, {
+		Kind:          FileDone,
+		Package:       "example.com/sample/util",
+		File:          filepath.Join(root, "util", "a.go"),
+		Files:         1,
+		TotalFiles:    2,
+		Packages:      1,
+		TotalPackages: 2,
+	}, {
+		Kind:          FileDone,
+		Package:       "example.com/sample/util",
+		File:          filepath.Join(root, "util", "b.go"),
+		Files:         2,
+		TotalFiles:    2,
+		Packages:      1,
+		TotalPackages: 2,
+	}, {
+		Kind:          PackageDone,
+		Package:       "example.com/sample/util",
+		Files:         2,
+		TotalFiles:    2,
+		Packages:      2,
+		TotalPackages: 2,
+	}}, events)
+}
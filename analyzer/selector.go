@@ -0,0 +1,162 @@
+package analyzer
+
+import (
+	"fmt"
+	"go/ast"
+	"go/types"
+	"strings"
+)
+
+// SegmentKind is kind of a segment in selector chain.
+type SegmentKind int
+
+const (
+	IdentSegment  SegmentKind = iota // operand of the chain, like c of c.Request()
+	FieldSegment                     // selected field or method which is not called, like Header
+	CallSegment                      // call of function or method, like Request()
+	IndexSegment                     // index operation, like [0]
+	AssertSegment                    // type assertion, like .(Handler)
+	ExprSegment                      // other expression as operand, like Config{} of Config{}.Load()
+)
+
+var segmentKindNames = []string{"ident", "field", "call", "index", "assert", "expr"}
+
+func (k SegmentKind) String() string {
+	if int(k) < len(segmentKindNames) {
+		return segmentKindNames[k]
+	}
+
+	return "unknown"
+}
+
+func (k SegmentKind) MarshalText() ([]byte, error) {
+	return []byte(k.String()), nil
+}
+
+func (k *SegmentKind) UnmarshalText(text []byte) error {
+	for index, name := range segmentKindNames {
+		if name == string(text) {
+			*k = SegmentKind(index)
+			return nil
+		}
+	}
+
+	return fmt.Errorf("unknown segment kind %q", text)
+}
+
+// Segment is an operation of selector chain. Name is name of identifier, field or called function,
+// index expression of IndexSegment, asserted type of AssertSegment, or the expression of ExprSegment.
+// Name of CallSegment is empty when the called function is not named, like the second call of f()().
+type Segment struct {
+	Kind SegmentKind `json:"kind"`
+	Name string      `json:"name,omitempty"`
+	Args []string    `json:"args,omitempty"` // arguments of CallSegment, as written
+}
+
+func (s Segment) String() string {
+	switch s.Kind {
+	case CallSegment:
+		return s.Name + "(" + strings.Join(s.Args, ", ") + ")"
+	case IndexSegment:
+		return "[" + s.Name + "]"
+	case AssertSegment:
+		return ".(" + s.Name + ")"
+	}
+
+	return s.Name
+}
+
+// SelectorChain is ordered segments of an expression, from its operand. c.Request().Header.Get("a") is
+// c, Request(), Header and Get("a").
+type SelectorChain []Segment
+
+func (c SelectorChain) String() string {
+	var b strings.Builder
+	for index, segment := range c {
+		if index != 0 && segment.Name != "" && (segment.Kind == FieldSegment || segment.Kind == CallSegment) {
+			b.WriteString(".")
+		}
+		b.WriteString(segment.String())
+	}
+
+	return b.String()
+}
+
+// Root returns the first segment, which the chain starts from.
+func (c SelectorChain) Root() (segment Segment, ok bool) {
+	if len(c) == 0 {
+		return
+	}
+
+	return c[0], true
+}
+
+// Last returns the last segment, which is the called function of a call chain.
+func (c SelectorChain) Last() (segment Segment, ok bool) {
+	if len(c) == 0 {
+		return
+	}
+
+	return c[len(c)-1], true
+}
+
+// Calls returns call segments of the chain in order.
+func (c SelectorChain) Calls() (calls []Segment) {
+	for _, segment := range c {
+		if segment.Kind == CallSegment {
+			calls = append(calls, segment)
+		}
+	}
+
+	return
+}
+
+// ParseChain parses selectors, calls, index operations and type assertions of x into ordered segments.
+func (p *Parser) ParseChain(pkgName string, x ast.Expr) (chain SelectorChain) {
+	switch x2 := x.(type) {
+	case *ast.Ident:
+		return SelectorChain{{Kind: IdentSegment, Name: x2.Name}}
+	case *ast.ParenExpr:
+		return p.ParseChain(pkgName, x2.X)
+	case *ast.SelectorExpr:
+		return append(p.ParseChain(pkgName, x2.X), Segment{Kind: FieldSegment, Name: x2.Sel.Name})
+	case *ast.CallExpr:
+		chain = p.ParseChain(pkgName, x2.Fun)
+		args := exprStrings(x2.Args)
+
+		// a() and x.a() call the last segment, and f()() calls result of the previous call
+		last := len(chain) - 1
+		if last >= 0 && (chain[last].Kind == IdentSegment || chain[last].Kind == FieldSegment) {
+			chain[last].Kind = CallSegment
+			chain[last].Args = args
+			return
+		}
+
+		return append(chain, Segment{Kind: CallSegment, Args: args})
+	case *ast.IndexExpr:
+		return append(p.ParseChain(pkgName, x2.X), Segment{Kind: IndexSegment, Name: types.ExprString(x2.Index)})
+	case *ast.IndexListExpr:
+		return append(p.ParseChain(pkgName, x2.X), Segment{Kind: IndexSegment, Name: strings.Join(exprStrings(x2.Indices), ", ")})
+	case *ast.TypeAssertExpr:
+		typ := "type" // x.(type) of type switch
+		if x2.Type != nil {
+			typ = types.ExprString(x2.Type)
+		}
+
+		return append(p.ParseChain(pkgName, x2.X), Segment{Kind: AssertSegment, Name: typ})
+	}
+
+	if x == nil {
+		return
+	}
+
+	return SelectorChain{{Kind: ExprSegment, Name: types.ExprString(x)}}
+}
+
+func exprStrings(xs []ast.Expr) (ss []string) {
+	for _, x := range xs {
+		ss = append(ss, types.ExprString(x))
+	}
+
+	return
+}
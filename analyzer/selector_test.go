@@ -0,0 +1,166 @@
+package analyzer
+
+import (
+	"bytes"
+	"go/parser"
+	"go/token"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParser_ParseChain(t *testing.T) {
+	tests := []struct {
+		name string
+		expr string
+		want SelectorChain
+	}{{
+		name: "메서드의 리턴값에서 필드를 거쳐 메서드를 호출하는 경우",
+		expr: `c.Request().Header.Get("a")`,
+		want: SelectorChain{
+			{Kind: IdentSegment, Name: "c"},
+			{Kind: CallSegment, Name: "Request"},
+			{Kind: FieldSegment, Name: "Header"},
+			{Kind: CallSegment, Name: "Get", Args: []string{`"a"`}},
+		},
+	}, {
+		name: "중첩된 selector인 경우",
+		expr: "a().b(x, y).c().d.e.f()",
+		want: SelectorChain{
+			{Kind: CallSegment, Name: "a"},
+			{Kind: CallSegment, Name: "b", Args: []string{"x", "y"}},
+			{Kind: CallSegment, Name: "c"},
+			{Kind: FieldSegment, Name: "d"},
+			{Kind: FieldSegment, Name: "e"},
+			{Kind: CallSegment, Name: "f"},
+		},
+	}, {
+		name: "인덱스와 타입 단언이 섞인 경우",
+		expr: "handlers[i+1].(Handler).Serve(w)",
+		want: SelectorChain{
+			{Kind: IdentSegment, Name: "handlers"},
+			{Kind: IndexSegment, Name: "i + 1"},
+			{Kind: AssertSegment, Name: "Handler"},
+			{Kind: CallSegment, Name: "Serve", Args: []string{"w"}},
+		},
+	}, {
+		name: "리턴된 함수를 호출하는 경우",
+		expr: "f()()",
+		want: SelectorChain{
+			{Kind: CallSegment, Name: "f"},
+			{Kind: CallSegment},
+		},
+	}, {
+		name: "복합 리터럴의 메서드를 호출하는 경우",
+		expr: "(Config{}).Load()",
+		want: SelectorChain{
+			{Kind: ExprSegment, Name: "Config{}"},
+			{Kind: CallSegment, Name: "Load"},
+		},
+	}}
+
+	p := Parser{}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			x, err := parser.ParseExpr(tt.expr)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got := p.ParseChain("sample", x)
+			assert.Equal(t, tt.want, got)
+
+			last, ok := got.Last()
+			assert.True(t, ok)
+			assert.Equal(t, tt.want[len(tt.want)-1], last)
+		})
+	}
+}
+
+func TestSelectorChain_String(t *testing.T) {
+	p := Parser{}
+	for _, expr := range []string{
+		`c.Request().Header.Get("a")`,
+		"a().b(x, y).c().d.e.f()",
+		"handlers[i + 1].(Handler).Serve(w)",
+		"f()()",
+		"Pair[int, string]{}.Swap()",
+	} {
+		x, err := parser.ParseExpr(expr)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, expr, p.ParseChain("sample", x).String())
+	}
+}
+
+func TestParser_ArrayLength(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+		want   string
+	}{{
+		name:   "길이가 상수인 배열을 변환하는 경우",
+		source: "func main() {[4]byte(b)}",
+		want:   "[4]byte",
+	}, {
+		name:   "길이가 식인 배열을 변환하는 경우",
+		source: "func main() {[N * 2]T(b)}",
+		want:   "[N * 2]T",
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fset, ce := getParsedFunctionCall(tt.source)
+			p := Parser{fset: fset}
+
+			assert.Equal(t, tt.want, p.ParseFuncCall("sample", ce).Name)
+			assert.Equal(t, tt.want, p.ParseType("sample", ce.Fun).String())
+			assert.Empty(t, p.Diagnostics())
+		})
+	}
+
+	prms := getParsedParameter("(a [4]byte, b *[N]T)")
+	p := Parser{fset: token.NewFileSet()}
+	assert.Equal(t, "[4]byte", p.ParseParameters(prms[0])[0].Type)
+	assert.Equal(t, "[N]T", p.ParseParameters(prms[1])[0].Type)
+}
+
+func TestSnapshot_Chain(t *testing.T) {
+	root := writeModule(t, map[string]string{
+		"go.mod": "module example.com/sample\n",
+		"main.go": `package main
+
+import "net/http"
+
+func main() {
+	r, _ := http.NewRequest("GET", "/", nil)
+	r.URL.Query().Get("q")
+}
+`,
+	})
+
+	p := NewParser(root)
+	assert.NoError(t, p.Parse())
+
+	var buf bytes.Buffer
+	assert.NoError(t, p.Save(&buf))
+
+	s, err := Load(&buf)
+	assert.NoError(t, err)
+
+	var chain SelectorChain
+	for _, call := range s.Calls {
+		if call.Name == "r.URL.Query().Get" {
+			chain = call.Chain
+		}
+	}
+
+	assert.Equal(t, SelectorChain{
+		{Kind: IdentSegment, Name: "r"},
+		{Kind: FieldSegment, Name: "URL"},
+		{Kind: CallSegment, Name: "Query"},
+		{Kind: CallSegment, Name: "Get", Args: []string{`"q"`}},
+	}, chain)
+}
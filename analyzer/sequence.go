@@ -0,0 +1,334 @@
+package analyzer
+
+import (
+	"go/ast"
+	"go/types"
+	"strings"
+)
+
+// SequenceDiagram returns mermaid sequence diagram of calls in the body of f, in source order.
+// Participants are receiver types, or packages for functions without receiver. Calls into declared functions
+// are followed up to depth levels, so 0 draws calls of f only. Loops become loop blocks, and if, switch and
+// select statements become alt blocks. Bodies of function literals are not drawn, because they may not be called in place.
+// Calls that are not resolved to a function of a package or a method of a known type are not drawn either.
+func (p Parser) SequenceDiagram(f *FunctionStatement, depth int) string {
+	s := sequence{
+		calls:    make(map[*ast.CallExpr]FunctionCall),
+		labels:   make(map[string]string),
+		visiting: make(map[string]bool),
+		depth:    depth,
+	}
+
+	for _, call := range p.functionCalls {
+		if call.expr != nil && !call.IsPossible {
+			s.calls[call.expr] = call
+		}
+	}
+
+	from := s.participant(f)
+	body := s.function(f, from, 0)
+
+	lines := []string{"sequenceDiagram"}
+	for _, id := range s.participants {
+		lines = append(lines, "    participant "+id+" as "+s.labels[id])
+	}
+	lines = append(lines, indent(body, "    ")...)
+
+	return strings.Join(lines, "\n") + "\n"
+}
+
+// sequence is a state of drawing sequence diagram.
+type sequence struct {
+	calls        map[*ast.CallExpr]FunctionCall
+	participants []string // ids of participants, in order of appearance
+	labels       map[string]string
+	visiting     map[string]bool // functions which are being drawn, to stop at recursive calls
+	depth        int
+}
+
+// participant returns id of participant that the function belongs to.
+func (s *sequence) participant(f *FunctionStatement) string {
+	pkg := f.Package
+	if f.ImportPath != "" {
+		pkg = f.ImportPath
+	}
+
+	return s.participantOf(pkg, f.Receiver.Type)
+}
+
+func (s *sequence) participantOf(pkg, receiver string) string {
+	label := pkg[strings.LastIndex(pkg, "/")+1:]
+	id := pkg
+	if receiver != "" {
+		label = receiver
+		id += "." + receiver
+	}
+	id = nonIDChar.ReplaceAllString(id, "_")
+
+	if _, ok := s.labels[id]; !ok {
+		s.participants = append(s.participants, id)
+		s.labels[id] = label
+	}
+
+	return id
+}
+
+func (s *sequence) function(f *FunctionStatement, from string, level int) []string {
+	if f.Body == nil {
+		return nil
+	}
+
+	s.visiting[f.Identifier()] = true
+	defer delete(s.visiting, f.Identifier())
+
+	return s.stmts(f.Body.List, from, level)
+}
+
+func (s *sequence) stmts(list []ast.Stmt, from string, level int) (lines []string) {
+	for _, stmt := range list {
+		lines = append(lines, s.stmt(stmt, from, level)...)
+	}
+
+	return
+}
+
+func (s *sequence) stmt(stmt ast.Stmt, from string, level int) (lines []string) {
+	switch x := stmt.(type) {
+	case *ast.BlockStmt:
+		return s.stmts(x.List, from, level)
+	case *ast.LabeledStmt:
+		return s.stmt(x.Stmt, from, level)
+	case *ast.GoStmt:
+		return s.expr(x.Call, from, level, "go ")
+	case *ast.DeferStmt:
+		return s.expr(x.Call, from, level, "defer ")
+	case *ast.IfStmt:
+		return s.ifStmt(x, append(s.stmt(x.Init, from, level), s.expr(x.Cond, from, level, "")...), from, level)
+	case *ast.ForStmt:
+		lines = s.stmt(x.Init, from, level)
+
+		label := "for"
+		if x.Cond != nil {
+			label += " " + types.ExprString(x.Cond)
+		}
+
+		body := append(s.expr(x.Cond, from, level, ""), s.stmts(x.Body.List, from, level)...)
+		body = append(body, s.stmt(x.Post, from, level)...)
+
+		return append(lines, block("loop "+label, body)...)
+	case *ast.RangeStmt:
+		lines = s.expr(x.X, from, level, "")
+		return append(lines, block("loop range "+types.ExprString(x.X), s.stmts(x.Body.List, from, level))...)
+	case *ast.SwitchStmt:
+		lines = append(s.stmt(x.Init, from, level), s.expr(x.Tag, from, level, "")...)
+		return append(lines, alt(s.clauses(x.Body, from, level))...)
+	case *ast.TypeSwitchStmt:
+		lines = append(s.stmt(x.Init, from, level), s.stmt(x.Assign, from, level)...)
+		return append(lines, alt(s.clauses(x.Body, from, level))...)
+	case *ast.SelectStmt:
+		return alt(s.clauses(x.Body, from, level))
+	case nil:
+		return nil
+	}
+
+	return s.expr(stmt, from, level, "")
+}
+
+// clauses returns branches of switch and select statements.
+func (s *sequence) clauses(body *ast.BlockStmt, from string, level int) (branches []branch) {
+	for _, clause := range body.List {
+		switch x := clause.(type) {
+		case *ast.CaseClause:
+			label := "default"
+			if x.List != nil {
+				label = "case " + strings.Join(exprStrings(x.List), ", ")
+			}
+
+			branches = append(branches, branch{label: label, lines: s.stmts(x.Body, from, level)})
+		case *ast.CommClause:
+			label := "default"
+			lines := s.stmt(x.Comm, from, level)
+			if x.Comm != nil {
+				label = "case"
+			}
+
+			branches = append(branches, branch{label: label, lines: append(lines, s.stmts(x.Body, from, level)...)})
+		}
+	}
+
+	return
+}
+
+// ifStmt draws x as alt block, after lines of its init and condition. When init or condition of an else if has
+// calls, they are drawn in the else branch, and the else if is nested under them as a new alt block.
+func (s *sequence) ifStmt(x *ast.IfStmt, lines []string, from string, level int) []string {
+	branches := []branch{{label: "if " + types.ExprString(x.Cond), lines: s.stmts(x.Body.List, from, level)}}
+	for els := x.Else; els != nil; {
+		switch e := els.(type) {
+		case *ast.IfStmt:
+			cond := append(s.stmt(e.Init, from, level), s.expr(e.Cond, from, level, "")...)
+			if e.Init != nil || len(cond) != 0 {
+				branches = append(branches, branch{lines: s.ifStmt(e, cond, from, level)})
+				els = nil
+				break
+			}
+
+			branches = append(branches, branch{label: "if " + types.ExprString(e.Cond), lines: s.stmts(e.Body.List, from, level)})
+			els = e.Else
+		case *ast.BlockStmt:
+			branches = append(branches, branch{lines: s.stmts(e.List, from, level)})
+			els = nil
+		}
+	}
+
+	return append(lines, alt(branches)...)
+}
+
+// expr draws calls in node, in order of evaluation. Arguments and receivers are called before the call.
+func (s *sequence) expr(node ast.Node, from string, level int, prefix string) (lines []string) {
+	if node == nil {
+		return
+	}
+
+	var calls []*ast.CallExpr
+	var stack []ast.Node
+	ast.Inspect(node, func(n ast.Node) bool {
+		if n == nil {
+			if ce, ok := stack[len(stack)-1].(*ast.CallExpr); ok {
+				calls = append(calls, ce)
+			}
+			stack = stack[:len(stack)-1]
+			return false
+		}
+
+		if _, ok := n.(*ast.FuncLit); ok {
+			return false
+		}
+
+		stack = append(stack, n)
+		return true
+	})
+
+	for index, ce := range calls {
+		call, ok := s.calls[ce]
+		if !ok {
+			continue
+		}
+
+		// prefix of go and defer statements is for the last call, which is the deferred call
+		p := ""
+		if index == len(calls)-1 {
+			p = prefix
+		}
+		lines = append(lines, s.call(call, from, level, p)...)
+	}
+
+	return
+}
+
+func (s *sequence) call(call FunctionCall, from string, level int, prefix string) []string {
+	decl := call.FunctionDeclaration
+
+	var to, name string
+	if decl != nil {
+		to, name = s.participant(decl), decl.Name
+	} else {
+		// Callee is set only when the call is resolved to a function of a package, or a method of a known type.
+		// Other calls, like methods of variables which type is unknown, have no participant to draw
+		if call.Callee == "" {
+			return nil
+		}
+
+		pkg, receiver, n := splitIdentifier(call.Callee)
+		if pkg == "" || (receiver == "" && types.Universe.Lookup(n) != nil) {
+			return nil // builtin function, or conversion into predeclared type
+		}
+
+		to, name = s.participantOf(pkg, receiver), n
+	}
+
+	message := escapeSequence(prefix + name + call.Parameters.String())
+	if decl == nil || level >= s.depth || s.visiting[decl.Identifier()] || decl.Body == nil {
+		return []string{from + "->>" + to + ": " + message}
+	}
+
+	lines := []string{from + "->>+" + to + ": " + message}
+	lines = append(lines, indent(s.function(decl, to, level+1), "    ")...)
+
+	return append(lines, "deactivate "+to)
+}
+
+// branch is a branch of alt block.
+type branch struct {
+	label string
+	lines []string
+}
+
+// alt returns alt block of the branches, or opt block when there is only one branch.
+// It returns nothing when no branch has a call.
+func alt(branches []branch) (lines []string) {
+	empty := true
+	for _, b := range branches {
+		empty = empty && len(b.lines) == 0
+	}
+	if empty {
+		return
+	}
+
+	for index, b := range branches {
+		keyword := "alt "
+		if len(branches) == 1 {
+			keyword = "opt "
+		} else if index != 0 {
+			keyword = "else "
+		}
+
+		lines = append(lines, strings.TrimSpace(keyword+escapeSequence(b.label)))
+		lines = append(lines, indent(b.lines, "    ")...)
+	}
+
+	return append(lines, "end")
+}
+
+// block returns loop block of the lines. It returns nothing when the block has no call.
+func block(label string, body []string) []string {
+	if len(body) == 0 {
+		return nil
+	}
+
+	lines := []string{escapeSequence(label)}
+	lines = append(lines, indent(body, "    ")...)
+
+	return append(lines, "end")
+}
+
+// escapeSequence escapes characters that end a line of mermaid sequence diagram.
+func escapeSequence(s string) string {
+	return strings.ReplaceAll(s, ";", "#59;")
+}
+
+func indent(lines []string, prefix string) []string {
+	indented := make([]string, 0, len(lines))
+	for _, line := range lines {
+		indented = append(indented, prefix+line)
+	}
+
+	return indented
+}
+
+// splitIdentifier splits identifier of function into package, receiver and name.
+// Package is the part before the first dot after the last slash, like net/http of net/http.Client.Do.
+func splitIdentifier(id string) (pkg, receiver, name string) {
+	slash := strings.LastIndex(id, "/") + 1
+	dot := strings.Index(id[slash:], ".")
+	if dot < 0 {
+		return "", "", id
+	}
+
+	pkg, name = id[:slash+dot], id[slash+dot+1:]
+	if index := strings.LastIndex(name, "."); index >= 0 {
+		receiver, name = name[:index], name[index+1:]
+	}
+
+	return
+}
@@ -0,0 +1,334 @@
+package analyzer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParser_SequenceDiagram(t *testing.T) {
+	root := writeModule(t, map[string]string{
+		"go.mod": "module example.com/sample\n",
+		"main.go": `package main
+
+import (
+	"fmt"
+
+	"example.com/sample/store"
+)
+
+type Handler struct {
+	store *store.Store
+}
+
+func (h *Handler) Serve(ids []string) error {
+	defer fmt.Println("done")
+
+	if len(ids) == 0 {
+		return h.fail("empty")
+	} else if len(ids) > 10 {
+		h.fail("too many")
+	}
+
+	for _, id := range ids {
+		h.store.Get(id)
+	}
+
+	switch ids[0] {
+	case "a", "b":
+		h.store.Put(ids[0])
+	default:
+	}
+
+	return nil
+}
+
+func (h *Handler) fail(reason string) error {
+	return fmt.Errorf(reason)
+}
+`,
+		"store/store.go": `package store
+
+type Store struct {
+	items map[string]string
+}
+
+func (s *Store) Get(id string) string {
+	s.touch()
+	return s.items[id]
+}
+
+func (s *Store) Put(id string) {
+	s.items[id] = id
+	s.touch()
+}
+
+func (s *Store) touch() {}
+`,
+	})
+
+	p := NewParser(root)
+	p.SetRecursive(true)
+	assert.NoError(t, p.Parse())
+
+	f, ok := p.Function("example.com/sample.Handler.Serve")
+	if !assert.True(t, ok) {
+		return
+	}
+
+	tests := []struct {
+		name  string
+		depth int
+		want  string
+	}{{
+		name:  "호출을 따라가지 않는 경우",
+		depth: 0,
+		want: `sequenceDiagram
+    participant example_com_sample_Handler as Handler
+    participant fmt as fmt
+    participant example_com_sample_store_Store as Store
+    example_com_sample_Handler->>fmt: defer Println("done")
+    alt if len(ids) == 0
+        example_com_sample_Handler->>example_com_sample_Handler: fail("empty")
+    else if len(ids) > 10
+        example_com_sample_Handler->>example_com_sample_Handler: fail("too many")
+    end
+    loop range ids
+        example_com_sample_Handler->>example_com_sample_store_Store: Get(id)
+    end
+    alt case "a", "b"
+        example_com_sample_Handler->>example_com_sample_store_Store: Put(ids[0])
+    else default
+    end
+`,
+	}, {
+		name:  "선언된 함수로 호출을 따라가는 경우",
+		depth: 1,
+		want: `sequenceDiagram
+    participant example_com_sample_Handler as Handler
+    participant fmt as fmt
+    participant example_com_sample_store_Store as Store
+    example_com_sample_Handler->>fmt: defer Println("done")
+    alt if len(ids) == 0
+        example_com_sample_Handler->>+example_com_sample_Handler: fail("empty")
+            example_com_sample_Handler->>fmt: Errorf(reason)
+        deactivate example_com_sample_Handler
+    else if len(ids) > 10
+        example_com_sample_Handler->>+example_com_sample_Handler: fail("too many")
+            example_com_sample_Handler->>fmt: Errorf(reason)
+        deactivate example_com_sample_Handler
+    end
+    loop range ids
+        example_com_sample_Handler->>+example_com_sample_store_Store: Get(id)
+            example_com_sample_store_Store->>example_com_sample_store_Store: touch()
+        deactivate example_com_sample_store_Store
+    end
+    alt case "a", "b"
+        example_com_sample_Handler->>+example_com_sample_store_Store: Put(ids[0])
+            example_com_sample_store_Store->>example_com_sample_store_Store: touch()
+        deactivate example_com_sample_store_Store
+    else default
+    end
+`,
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, p.SequenceDiagram(f, tt.depth))
+		})
+	}
+}
+
+func TestParser_SequenceDiagramRecursion(t *testing.T) {
+	root := writeModule(t, map[string]string{
+		"go.mod": "module example.com/sample\n",
+		"main.go": `package main
+
+func walk(n int) {
+	for i := 0; i < n; i++ {
+		walk(n - 1)
+	}
+}
+`,
+	})
+
+	p := NewParser(root)
+	p.SetRecursive(true)
+	assert.NoError(t, p.Parse())
+
+	f, ok := p.Function("example.com/sample.walk")
+	if !assert.True(t, ok) {
+		return
+	}
+
+	assert.Equal(t, `sequenceDiagram
+    participant example_com_sample as sample
+    loop for i < n
+        example_com_sample->>example_com_sample: walk(n - 1)
+    end
+`, p.SequenceDiagram(f, 5))
+}
+
+func TestParser_SequenceDiagramNestedCalls(t *testing.T) {
+	root := writeModule(t, map[string]string{
+		"go.mod": "module example.com/sample\n",
+		"main.go": `package main
+
+import (
+	"fmt"
+
+	"example.com/sample/util"
+)
+
+func main() {
+	fmt.Println(util.NewServer())
+	util.NewServer().Start()
+	defer fmt.Println(util.Name())
+}
+`,
+		"util/util.go": `package util
+
+type Server struct{}
+
+func NewServer() *Server {
+	return &Server{}
+}
+
+func (s *Server) Start() {}
+
+func Name() string {
+	return "util"
+}
+`,
+	})
+
+	p := NewParser(root)
+	p.SetRecursive(true)
+	assert.NoError(t, p.Parse())
+
+	f, ok := p.Function("example.com/sample.main")
+	if !assert.True(t, ok) {
+		return
+	}
+
+	// arguments and receivers are called before the call, and defer is for the deferred call only
+	assert.Equal(t, `sequenceDiagram
+    participant example_com_sample as sample
+    participant example_com_sample_util as util
+    participant fmt as fmt
+    participant example_com_sample_util_Server as Server
+    example_com_sample->>example_com_sample_util: NewServer()
+    example_com_sample->>fmt: Println(util.NewServer())
+    example_com_sample->>example_com_sample_util: NewServer()
+    example_com_sample->>example_com_sample_util_Server: Start()
+    example_com_sample->>example_com_sample_util: Name()
+    example_com_sample->>fmt: defer Println(util.Name())
+`, p.SequenceDiagram(f, 0))
+}
+
+func TestParser_SequenceDiagramElseIf(t *testing.T) {
+	root := writeModule(t, map[string]string{
+		"go.mod": "module example.com/sample\n",
+		"main.go": `package main
+
+import "example.com/sample/auth"
+
+func serve(token string) {
+	if token == "" {
+		reject()
+	} else if auth.Check(token) {
+		accept()
+	} else {
+		reject()
+	}
+}
+
+func accept() {}
+
+func reject() {}
+`,
+		"auth/auth.go": `package auth
+
+import "strings"
+
+func Check(token string) bool {
+	return strings.HasPrefix(token, "ok")
+}
+`,
+	})
+
+	p := NewParser(root)
+	p.SetRecursive(true)
+	assert.NoError(t, p.Parse())
+
+	f, ok := p.Function("example.com/sample.serve")
+	if !assert.True(t, ok) {
+		return
+	}
+
+	// call in condition of else if is drawn in the else branch, and the else if is nested as a new alt block
+	assert.Equal(t, `sequenceDiagram
+    participant example_com_sample as sample
+    participant example_com_sample_auth as auth
+    participant strings as strings
+    alt if token == ""
+        example_com_sample->>+example_com_sample: reject()
+        deactivate example_com_sample
+    else
+        example_com_sample->>+example_com_sample_auth: Check(token)
+            example_com_sample_auth->>strings: HasPrefix(token, "ok")
+        deactivate example_com_sample_auth
+        alt if auth.Check(token)
+            example_com_sample->>+example_com_sample: accept()
+            deactivate example_com_sample
+        else
+            example_com_sample->>+example_com_sample: reject()
+            deactivate example_com_sample
+        end
+    end
+`, p.SequenceDiagram(f, 1))
+}
+
+func TestParser_SequenceDiagramUnresolved(t *testing.T) {
+	root := writeModule(t, map[string]string{
+		"go.mod": "module example.com/sample\n",
+		"main.go": `package main
+
+import (
+	"net/http"
+	"strings"
+)
+
+func handle(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	<-ctx.Done()
+	w.Header().Set("Content-Type", strings.ToLower("TEXT/PLAIN"))
+	done()
+}
+
+func done() {}
+`,
+	})
+
+	p := NewParser(root)
+	p.SetRecursive(true)
+	assert.NoError(t, p.Parse())
+
+	f, ok := p.Function("example.com/sample.handle")
+	if !assert.True(t, ok) {
+		return
+	}
+
+	// methods of ctx and of the result of w.Header() are not resolved in syntactic mode, so they are not drawn.
+	// receivers of parameters have known types, so they are participants
+	assert.Equal(t, `sequenceDiagram
+    participant example_com_sample as sample
+    participant net_http_Request as Request
+    participant net_http_ResponseWriter as ResponseWriter
+    participant strings as strings
+    example_com_sample->>net_http_Request: Context()
+    example_com_sample->>net_http_ResponseWriter: Header()
+    example_com_sample->>strings: ToLower("TEXT/PLAIN")
+    example_com_sample->>example_com_sample: done()
+`, p.SequenceDiagram(f, 0))
+}
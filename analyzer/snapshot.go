@@ -0,0 +1,302 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"fmt"
+	"go/token"
+	"io"
+	"sort"
+)
+
+// SnapshotVersion is increased whenever layout of Snapshot is changed.
+// Layout of each version is documented in SNAPSHOT.md at the root of the repository.
+const SnapshotVersion = 2
+
+// Snapshot is a serializable result of analysis. It does not hold any ast.Node, so it can be saved and loaded later.
+// Functions, structures and interfaces refer each other by identifiers instead of pointers.
+type Snapshot struct {
+	Version    int                 `json:"version"`
+	ModulePath string              `json:"module_path,omitempty"`
+	Functions  []FunctionSnapshot  `json:"functions"`
+	Calls      []CallSnapshot      `json:"calls"`
+	Structures []StructureSnapshot `json:"structures"`
+	Interfaces []InterfaceSnapshot `json:"interfaces"`
+	Imports    []FileImports       `json:"imports"`
+}
+
+// FunctionSnapshot is a declared function or method. Identifier is like example.com/sample.Server.Run.
+type FunctionSnapshot struct {
+	Identifier string     `json:"identifier"`
+	Package    string     `json:"package"`
+	ImportPath string     `json:"import_path,omitempty"`
+	Name       string     `json:"name"`
+	Receiver   Parameter  `json:"receiver"`
+	TypeParams Parameters `json:"type_params,omitempty"`
+	Parameters Parameters `json:"parameters"`
+	Returns    Parameters `json:"returns"`
+	Signature  string     `json:"signature"`
+	Pos        Position   `json:"pos"`
+	End        Position   `json:"end"`
+}
+
+// CallSnapshot is a function call. Caller is identifier of function that the call is written in,
+// and it is empty when the call is not in a function, like initializing package variable.
+type CallSnapshot struct {
+	Caller    string        `json:"caller,omitempty"`
+	Callee    string        `json:"callee"`
+	Name      string        `json:"name"`
+	TypeArgs  []string      `json:"type_args,omitempty"`
+	Chain     SelectorChain `json:"chain,omitempty"`
+	Resolved  bool          `json:"resolved"`
+	Possible  bool          `json:"possible,omitempty"`
+	FuncValue bool          `json:"func_value,omitempty"`
+	Pos       Position      `json:"pos"`
+}
+
+// StructureSnapshot is a declared structure. Methods are identifiers of functions.
+type StructureSnapshot struct {
+	Identifier string     `json:"identifier"`
+	Package    string     `json:"package"`
+	ImportPath string     `json:"import_path,omitempty"`
+	Name       string     `json:"name"`
+	TypeParams Parameters `json:"type_params,omitempty"`
+	Fields     Parameters `json:"fields"`
+	Methods    []string   `json:"methods"`
+	Pos        Position   `json:"pos"`
+}
+
+// InterfaceSnapshot is a declared interface. Embedded are identifiers of embedded interfaces, and Implementations
+// are identifiers of parsed types that implement the interface, with * when only pointer of the type implements it.
+type InterfaceSnapshot struct {
+	Identifier      string           `json:"identifier"`
+	Package         string           `json:"package"`
+	ImportPath      string           `json:"import_path,omitempty"`
+	Name            string           `json:"name"`
+	TypeParams      Parameters       `json:"type_params,omitempty"`
+	Methods         []MethodSnapshot `json:"methods"`
+	Embedded        []string         `json:"embedded"`
+	Implementations []string         `json:"implementations"`
+	Pos             Position         `json:"pos"`
+}
+
+// MethodSnapshot is a method signature of interface.
+type MethodSnapshot struct {
+	Name       string     `json:"name"`
+	Parameters Parameters `json:"parameters"`
+	Returns    Parameters `json:"returns"`
+	Signature  string     `json:"signature"`
+}
+
+// FileImports is imports of a file. File is path of the file.
+type FileImports struct {
+	File    string   `json:"file"`
+	Imports []Import `json:"imports"`
+}
+
+// Snapshot takes serializable result of analysis. Every list is sorted, so same source code gives same snapshot.
+func (p Parser) Snapshot() (s Snapshot) {
+	s.Version = SnapshotVersion
+	s.ModulePath = p.modulePath
+
+	s.Functions = make([]FunctionSnapshot, 0, len(p.functionsByName))
+	for _, f := range p.functionsByName {
+		s.Functions = append(s.Functions, FunctionSnapshot{
+			Identifier: f.Identifier(),
+			Package:    f.Package,
+			ImportPath: f.ImportPath,
+			Name:       f.Name,
+			Receiver:   untyped(Parameters{f.Receiver})[0],
+			TypeParams: f.TypeParams,
+			Parameters: untyped(f.Parameters),
+			Returns:    untyped(f.Returns),
+			Signature:  f.String(),
+			Pos:        position(p.fset, f.SourceCode.Pos),
+			End:        position(p.fset, f.SourceCode.End),
+		})
+	}
+	sort.Slice(s.Functions, func(i, j int) bool {
+		return s.Functions[i].Identifier < s.Functions[j].Identifier
+	})
+
+	s.Calls = make([]CallSnapshot, 0, len(p.functionCalls))
+	for _, fc := range p.functionCalls {
+		call := CallSnapshot{
+			Callee:    fc.Identifier(),
+			Name:      fc.Name,
+			TypeArgs:  fc.TypeArgs,
+			Chain:     fc.Chain,
+			Resolved:  fc.FunctionDeclaration != nil,
+			Possible:  fc.IsPossible,
+			FuncValue: fc.IsFuncValue,
+			Pos:       position(p.fset, token.Pos(fc.Pos)),
+		}
+
+		if fc.Parent != nil {
+			call.Caller = fc.Parent.Identifier()
+		}
+
+		if fc.FunctionDeclaration != nil {
+			call.Callee = fc.FunctionDeclaration.Identifier()
+		}
+
+		s.Calls = append(s.Calls, call)
+	}
+	sort.SliceStable(s.Calls, func(i, j int) bool {
+		return lessPosition(s.Calls[i].Pos, s.Calls[j].Pos)
+	})
+
+	s.Structures = make([]StructureSnapshot, 0, len(p.structureTypes))
+	for _, strct := range p.structureTypes {
+		methods := make([]string, 0, len(strct.methods))
+		for _, m := range strct.methods {
+			methods = append(methods, m.Identifier())
+		}
+		sort.Strings(methods)
+
+		s.Structures = append(s.Structures, StructureSnapshot{
+			Identifier: strct.Identifier(),
+			Package:    strct.PkgName,
+			ImportPath: strct.ImportPath,
+			Name:       strct.Name,
+			TypeParams: strct.TypeParams,
+			Fields:     untyped(strct.Parameters),
+			Methods:    methods,
+			Pos:        position(p.fset, strct.Pos),
+		})
+	}
+	sort.Slice(s.Structures, func(i, j int) bool {
+		return s.Structures[i].Identifier < s.Structures[j].Identifier
+	})
+
+	s.Interfaces = make([]InterfaceSnapshot, 0, len(p.interfaceTypes))
+	for _, iface := range p.Interfaces() {
+		methods := make([]MethodSnapshot, 0, len(iface.Methods))
+		for _, m := range iface.Methods {
+			methods = append(methods, MethodSnapshot{
+				Name:       m.Name,
+				Parameters: untyped(m.Parameters),
+				Returns:    untyped(m.Returns),
+				Signature:  m.String(),
+			})
+		}
+
+		implementations := make([]string, 0)
+		for _, implementation := range p.Implementations(iface.Identifier()) {
+			typ := implementation.Type
+			if implementation.Pointer {
+				typ = "*" + typ
+			}
+			implementations = append(implementations, typ)
+		}
+		sort.Strings(implementations)
+
+		s.Interfaces = append(s.Interfaces, InterfaceSnapshot{
+			Identifier:      iface.Identifier(),
+			Package:         iface.PkgName,
+			ImportPath:      iface.ImportPath,
+			Name:            iface.Name,
+			TypeParams:      iface.TypeParams,
+			Methods:         methods,
+			Embedded:        append([]string{}, iface.Embedded...),
+			Implementations: implementations,
+			Pos:             position(p.fset, iface.Pos),
+		})
+	}
+
+	s.Imports = make([]FileImports, 0, len(p.importTable))
+	for file, imports := range p.importTable {
+		fi := FileImports{
+			File:    file,
+			Imports: make([]Import, 0, len(imports)),
+		}
+
+		for _, imp := range imports {
+			fi.Imports = append(fi.Imports, imp)
+		}
+		sort.Slice(fi.Imports, func(i, j int) bool {
+			return fi.Imports[i].Path < fi.Imports[j].Path
+		})
+
+		s.Imports = append(s.Imports, fi)
+	}
+	sort.Slice(s.Imports, func(i, j int) bool {
+		return s.Imports[i].File < s.Imports[j].File
+	})
+
+	return
+}
+
+// Save writes result of analysis into w.
+func (p Parser) Save(w io.Writer) error {
+	return p.Snapshot().Save(w)
+}
+
+func (s Snapshot) Save(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+
+	return encoder.Encode(s)
+}
+
+// untyped drops types of parameters, which are not serializable.
+func untyped(ps Parameters) Parameters {
+	if ps == nil {
+		return nil
+	}
+
+	u := make(Parameters, len(ps))
+	for index, prm := range ps {
+		prm.Object = nil
+		prm.TypeInfo = nil
+		u[index] = prm
+	}
+
+	return u
+}
+
+// Load reads snapshot that written by Save. It fails when the snapshot is written by other version.
+func Load(r io.Reader) (s Snapshot, err error) {
+	if err = json.NewDecoder(r).Decode(&s); err != nil {
+		return
+	}
+
+	if s.Version != SnapshotVersion {
+		err = fmt.Errorf("unsupported snapshot version %d, want %d", s.Version, SnapshotVersion)
+	}
+
+	return
+}
+
+// Function finds function by identifier.
+func (s Snapshot) Function(identifier string) (f FunctionSnapshot, ok bool) {
+	index := sort.Search(len(s.Functions), func(i int) bool {
+		return s.Functions[i].Identifier >= identifier
+	})
+
+	if index < len(s.Functions) && s.Functions[index].Identifier == identifier {
+		return s.Functions[index], true
+	}
+
+	return
+}
+
+// Position is a position in source file, like token.Position. Offset is a byte offset from 0, and Line and Column start at 1.
+type Position struct {
+	Filename string `json:"filename"`
+	Offset   int    `json:"offset"`
+	Line     int    `json:"line"`
+	Column   int    `json:"column"`
+}
+
+func position(fset *token.FileSet, pos token.Pos) Position {
+	p := fset.Position(pos)
+	return Position{Filename: p.Filename, Offset: p.Offset, Line: p.Line, Column: p.Column}
+}
+
+func lessPosition(a, b Position) bool {
+	if a.Filename != b.Filename {
+		return a.Filename < b.Filename
+	}
+
+	return a.Offset < b.Offset
+}
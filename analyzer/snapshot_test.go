@@ -0,0 +1,279 @@
+package analyzer
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSnapshot_SaveLoad(t *testing.T) {
+	root := writeModule(t, map[string]string{
+		"go.mod": "module example.com/sample\n",
+		"main.go": `package main
+
+import "example.com/sample/util"
+
+func main() {
+	s := util.NewServer()
+	s.Start()
+}
+`,
+		"util/util.go": `package util
+
+type Server struct {
+	Name string
+}
+
+func NewServer() *Server {
+	return &Server{}
+}
+
+func (s *Server) Start() {}
+`,
+	})
+
+	p := NewParser(root)
+	p.SetRecursive(true)
+	assert.NoError(t, p.Parse())
+
+	var buf bytes.Buffer
+	assert.NoError(t, p.Save(&buf))
+
+	loaded, err := Load(&buf)
+	assert.NoError(t, err)
+	assert.Equal(t, p.Snapshot(), loaded)
+
+	assert.Equal(t, "example.com/sample", loaded.ModulePath)
+
+	f, ok := loaded.Function("example.com/sample/util.Server.Start")
+	if assert.True(t, ok) {
+		assert.Equal(t, filepath.Join(root, "util", "util.go"), f.Pos.Filename)
+		assert.Equal(t, 11, f.Pos.Line)
+	}
+
+	if assert.Len(t, loaded.Calls, 2) {
+		assert.Equal(t, "example.com/sample.main", loaded.Calls[0].Caller)
+		assert.Equal(t, "example.com/sample/util.NewServer", loaded.Calls[0].Callee)
+		assert.Equal(t, "example.com/sample/util.Server.Start", loaded.Calls[1].Callee)
+		assert.True(t, loaded.Calls[1].Resolved)
+	}
+
+	if assert.Len(t, loaded.Structures, 1) {
+		assert.Equal(t, []string{"example.com/sample/util.Server.Start"}, loaded.Structures[0].Methods)
+		assert.Equal(t, 3, loaded.Structures[0].Pos.Line)
+	}
+}
+
+func TestLoad_Version(t *testing.T) {
+	_, err := Load(strings.NewReader(`{"version": 0}`))
+	assert.Error(t, err)
+
+	_, err = Load(strings.NewReader(`{`))
+	assert.Error(t, err)
+}
+
+// TestSnapshot_Schema keeps json keys of snapshot, which are documented in SNAPSHOT.md.
+func TestSnapshot_Schema(t *testing.T) {
+	root := writeModule(t, map[string]string{
+		"go.mod": "module example.com/sample\n",
+		"main.go": `package main
+
+import "fmt"
+
+type Runner interface {
+	Run(name string) error
+}
+
+type Server struct {
+	Name string
+}
+
+func (s *Server) Run(name string) error {
+	fmt.Println(name)
+	return nil
+}
+`,
+	})
+
+	p := NewParser(root)
+	assert.NoError(t, p.Parse())
+
+	var buf bytes.Buffer
+	assert.NoError(t, p.Save(&buf))
+
+	got := strings.ReplaceAll(buf.String(), filepath.ToSlash(root), "ROOT")
+	assert.Equal(t, `{
+  "version": 2,
+  "module_path": "example.com/sample",
+  "functions": [
+    {
+      "identifier": "example.com/sample.Server.Run",
+      "package": "main",
+      "import_path": "example.com/sample",
+      "name": "Run",
+      "receiver": {
+        "pkg": "main",
+        "name": "s",
+        "is_pointer": true,
+        "type": "Server",
+        "typ": {
+          "kind": "pointer",
+          "elem": {
+            "kind": "named",
+            "path": "example.com/sample",
+            "name": "Server"
+          }
+        }
+      },
+      "parameters": [
+        {
+          "pkg": "main",
+          "name": "name",
+          "type": "string",
+          "typ": {
+            "kind": "named",
+            "name": "string"
+          }
+        }
+      ],
+      "returns": [
+        {
+          "pkg": "main",
+          "type": "error",
+          "typ": {
+            "kind": "named",
+            "name": "error"
+          }
+        }
+      ],
+      "signature": "func (s *Server) Run(name string) error",
+      "pos": {
+        "filename": "ROOT/main.go",
+        "offset": 116,
+        "line": 13,
+        "column": 1
+      },
+      "end": {
+        "filename": "ROOT/main.go",
+        "offset": 190,
+        "line": 16,
+        "column": 2
+      }
+    }
+  ],
+  "calls": [
+    {
+      "caller": "example.com/sample.Server.Run",
+      "callee": "fmt.Println",
+      "name": "fmt.Println",
+      "chain": [
+        {
+          "kind": "ident",
+          "name": "fmt"
+        },
+        {
+          "kind": "call",
+          "name": "Println",
+          "args": [
+            "name"
+          ]
+        }
+      ],
+      "resolved": false,
+      "pos": {
+        "filename": "ROOT/main.go",
+        "offset": 159,
+        "line": 14,
+        "column": 2
+      }
+    }
+  ],
+  "structures": [
+    {
+      "identifier": "example.com/sample.Server",
+      "package": "main",
+      "import_path": "example.com/sample",
+      "name": "Server",
+      "fields": [
+        {
+          "name": "Name",
+          "type": "string",
+          "typ": {
+            "kind": "named",
+            "name": "string"
+          }
+        }
+      ],
+      "methods": [
+        "example.com/sample.Server.Run"
+      ],
+      "pos": {
+        "filename": "ROOT/main.go",
+        "offset": 84,
+        "line": 9,
+        "column": 6
+      }
+    }
+  ],
+  "interfaces": [
+    {
+      "identifier": "example.com/sample.Runner",
+      "package": "main",
+      "import_path": "example.com/sample",
+      "name": "Runner",
+      "methods": [
+        {
+          "name": "Run",
+          "parameters": [
+            {
+              "pkg": "main",
+              "name": "name",
+              "type": "string",
+              "typ": {
+                "kind": "named",
+                "name": "string"
+              }
+            }
+          ],
+          "returns": [
+            {
+              "pkg": "main",
+              "type": "error",
+              "typ": {
+                "kind": "named",
+                "name": "error"
+              }
+            }
+          ],
+          "signature": "Run(name string)(error)"
+        }
+      ],
+      "embedded": [],
+      "implementations": [
+        "*example.com/sample.Server"
+      ],
+      "pos": {
+        "filename": "ROOT/main.go",
+        "offset": 33,
+        "line": 5,
+        "column": 6
+      }
+    }
+  ],
+  "imports": [
+    {
+      "file": "ROOT/main.go",
+      "imports": [
+        {
+          "name": "fmt",
+          "path": "fmt"
+        }
+      ]
+    }
+  ]
+}
+`, got)
+}
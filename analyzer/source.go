@@ -0,0 +1,99 @@
+package analyzer
+
+import (
+	"errors"
+	"fmt"
+	"go/token"
+	"os"
+	"time"
+)
+
+// ErrSourceChanged is returned by SourceCode.ReadText when the file is changed after parsing.
+var ErrSourceChanged = errors.New("source file is changed after parsing")
+
+// sourceFile is where content of a file comes from. Content of files in directories is not kept after parsing,
+// so memory does not grow with the parsed tree. It is read again through the file when text is needed,
+// and size of token.File and modification time at parsing tell whether it is still the parsed content.
+// Content of ParseFile can not be read again, so it is kept.
+type sourceFile struct {
+	name    string
+	base    int
+	size    int
+	modTime time.Time
+	data    []byte
+}
+
+func (f *sourceFile) bytes() ([]byte, error) {
+	if f.data != nil {
+		return f.data, nil
+	}
+
+	info, err := os.Stat(f.name)
+	if err != nil {
+		return nil, err
+	}
+	if !info.ModTime().Equal(f.modTime) || info.Size() != int64(f.size) {
+		return nil, fmt.Errorf("%w: %s", ErrSourceChanged, f.name)
+	}
+
+	data, err := os.ReadFile(f.name)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) != f.size {
+		return nil, fmt.Errorf("%w: %s", ErrSourceChanged, f.name)
+	}
+
+	return data, nil
+}
+
+// keepSource keeps content of ParseFile, which can not be read from disk later.
+func (p *Parser) keepSource(file *token.File, data []byte) {
+	if file == nil {
+		return
+	}
+
+	p.sources[file.Name()] = &sourceFile{name: file.Name(), base: file.Base(), size: file.Size(), data: data}
+}
+
+// keepSourceInfo keeps where the parsed file is, to read its content again when it is needed.
+func (p *Parser) keepSourceInfo(file *token.File, modTime time.Time) {
+	if file == nil {
+		return
+	}
+
+	p.sources[file.Name()] = &sourceFile{name: file.Name(), base: file.Base(), size: file.Size(), modTime: modTime}
+}
+
+// source returns where content of the file comes from.
+func (p *Parser) source(file *token.File) *sourceFile {
+	return p.sources[file.Name()]
+}
+
+// Text returns source code between Pos and End. It replaces SourceCode.Data, which was removed
+// because every function kept its own copy of the whole file.
+// It is empty when the file can not be read, or is changed after parsing. ReadText returns the reason.
+func (sc SourceCode) Text() string {
+	text, _ := sc.ReadText()
+	return text
+}
+
+// ReadText is Text, which returns error when the file can not be read. Files of directories are read again
+// when it is called, and it returns ErrSourceChanged when the file is not the same with the parsed one.
+func (sc SourceCode) ReadText() (string, error) {
+	if sc.source == nil {
+		return "", nil
+	}
+
+	data, err := sc.source.bytes()
+	if err != nil {
+		return "", err
+	}
+
+	start, end := int(sc.Pos)-sc.source.base, int(sc.End)-sc.source.base
+	if start < 0 || end > len(data) || start > end {
+		return "", fmt.Errorf("%w: %s", ErrSourceChanged, sc.source.name)
+	}
+
+	return string(data[start:end]), nil
+}
@@ -0,0 +1,98 @@
+package analyzer
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const sourceTestFile = `package main
+
+func main() {
+	run()
+}
+
+func run() {}
+`
+
+func TestSourceCode_Text(t *testing.T) {
+	root := writeModule(t, map[string]string{
+		"go.mod":  "module example.com/sample\n",
+		"main.go": sourceTestFile,
+	})
+
+	t.Run("디렉토리를 파싱하는 경우", func(t *testing.T) {
+		p := NewParser(root)
+		assert.NoError(t, p.Parse())
+
+		// content of the file is not kept after parsing
+		if assert.Len(t, p.sources, 1) {
+			assert.Nil(t, p.sources[filepath.Join(root, "main.go")].data)
+		}
+
+		main, ok := p.Function("example.com/sample.main")
+		if assert.True(t, ok) {
+			assert.Equal(t, "func main() {\n\trun()\n}", main.SourceCode.Text())
+			assert.Equal(t, filepath.Join(root, "main.go"), main.Path)
+		}
+
+		run, ok := p.Function("example.com/sample.run")
+		if assert.True(t, ok) {
+			assert.Equal(t, "func run() {}", run.SourceCode.Text())
+		}
+
+		assert.Len(t, p.sources, 1)
+	})
+
+	t.Run("소스 코드를 파싱하는 경우", func(t *testing.T) {
+		p := NewParser(filepath.Join(root, "other.go"))
+		assert.NoError(t, p.ParseFile(sourceTestFile))
+
+		run, ok := p.Function("example.com/sample.run")
+		if assert.True(t, ok) {
+			assert.Equal(t, "func run() {}", run.SourceCode.Text())
+		}
+	})
+
+	t.Run("파싱한 뒤 파일이 바뀐 경우", func(t *testing.T) {
+		p := NewParser(root)
+		assert.NoError(t, p.Parse())
+
+		assert.NoError(t, os.WriteFile(filepath.Join(root, "main.go"), []byte("package main\n"), 0644))
+		t.Cleanup(func() {
+			_ = os.WriteFile(filepath.Join(root, "main.go"), []byte(sourceTestFile), 0644)
+		})
+
+		main, ok := p.Function("example.com/sample.main")
+		if assert.True(t, ok) {
+			assert.Empty(t, main.SourceCode.Text())
+
+			_, err := main.SourceCode.ReadText()
+			assert.ErrorIs(t, err, ErrSourceChanged)
+		}
+	})
+
+	t.Run("파싱한 뒤 같은 크기로 파일이 바뀐 경우", func(t *testing.T) {
+		p := NewParser(root)
+		assert.NoError(t, p.Parse())
+
+		fileName := filepath.Join(root, "main.go")
+		changed := strings.Replace(sourceTestFile, "run()", "nop()", 1)
+		assert.NoError(t, os.WriteFile(fileName, []byte(changed), 0644))
+		t.Cleanup(func() {
+			_ = os.WriteFile(fileName, []byte(sourceTestFile), 0644)
+		})
+		later := time.Now().Add(time.Minute)
+		assert.NoError(t, os.Chtimes(fileName, later, later))
+
+		main, ok := p.Function("example.com/sample.main")
+		if assert.True(t, ok) {
+			_, err := main.SourceCode.ReadText()
+			assert.ErrorIs(t, err, ErrSourceChanged)
+		}
+	})
+}
@@ -0,0 +1,535 @@
+package analyzer
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+	"strings"
+)
+
+type ScopeKind int
+
+const (
+	PackageScope ScopeKind = iota + 1
+	FileScope
+	FunctionScope
+	BlockScope
+	ClosureScope
+)
+
+func (k ScopeKind) String() string {
+	switch k {
+	case PackageScope:
+		return "package"
+	case FileScope:
+		return "file"
+	case FunctionScope:
+		return "function"
+	case BlockScope:
+		return "block"
+	case ClosureScope:
+		return "closure"
+	}
+
+	return "unknown"
+}
+
+type SymbolKind int
+
+const (
+	VariableSymbol SymbolKind = iota + 1
+	ParameterSymbol
+	ReceiverSymbol
+	ResultSymbol
+)
+
+// Symbol is a variable that declared in a scope.
+// Type is qualified name of the type, like "github.com/labstack/echo/v4.Echo" or "[]int".
+// Pointer of named type is not kept in Type. Type is empty when it can not be inferred.
+type Symbol struct {
+	Name      string
+	Kind      SymbolKind
+	Type      string
+	IsPointer bool
+	Pos       token.Pos
+
+	origin    *typeOrigin
+	resolving bool
+}
+
+// resolveType infers type from the expression which is assigned into the symbol.
+// It should be called after every package is parsed, because origin may refer functions of other packages.
+// When the type can not be inferred yet, it is tried again on the next call.
+func (s *Symbol) resolveType(p *Parser) string {
+	if s.Type != "" || s.origin == nil || s.resolving {
+		return s.Type
+	}
+
+	s.resolving = true
+	s.Type = s.origin.resolve(p)
+	s.resolving = false
+	if s.Type != "" {
+		// origin is kept until it is resolved, because it may refer declarations of files parsed later
+		s.origin = nil
+	}
+
+	return s.Type
+}
+
+type SymbolTable struct {
+	Parent   *SymbolTable
+	Kind     ScopeKind
+	Node     ast.Node
+	symbols  map[string]*Symbol
+	order    []*Symbol
+	Children []*SymbolTable
+}
+
+func NewSymbolTable(parent *SymbolTable, kind ScopeKind, node ast.Node) *SymbolTable {
+	st := &SymbolTable{
+		Parent:  parent,
+		Kind:    kind,
+		Node:    node,
+		symbols: make(map[string]*Symbol),
+	}
+
+	if parent != nil {
+		parent.Children = append(parent.Children, st)
+	}
+
+	return st
+}
+
+// Insert adds symbol into the scope. Symbol which has same name is shadowed.
+func (st *SymbolTable) Insert(symbol *Symbol) {
+	if symbol.Name == "" || symbol.Name == "_" {
+		return
+	}
+
+	st.symbols[symbol.Name] = symbol
+	st.order = append(st.order, symbol)
+}
+
+// Lookup finds symbol from the scope, and from its parents.
+func (st *SymbolTable) Lookup(name string) (symbol *Symbol, ok bool) {
+	for scope := st; scope != nil; scope = scope.Parent {
+		if symbol, ok = scope.symbols[name]; ok {
+			return
+		}
+	}
+
+	return nil, false
+}
+
+// Symbols returns symbols that declared in the scope, in declaration order.
+func (st *SymbolTable) Symbols() []*Symbol {
+	return st.order
+}
+
+func (st *SymbolTable) resolve(p *Parser) {
+	for _, symbol := range st.order {
+		symbol.resolveType(p)
+	}
+
+	for _, child := range st.Children {
+		child.resolve(p)
+	}
+}
+
+// typeOrigin describes where the type of a value comes from, when it can not be decided while parsing.
+type typeOrigin struct {
+	typ       string      // already known type
+	symbol    *Symbol     // assigned from other variable
+	callee    string      // result of function, or conversion into named type
+	receiver  *typeOrigin // result of method, or field of struct
+	method    string
+	field     string
+	result    int
+	container *typeOrigin // element or key of slice, array, map or channel
+	element   bool
+	key       bool
+}
+
+func (o *typeOrigin) resolve(p *Parser) (typ string) {
+	switch {
+	case o.typ != "":
+		typ = o.typ
+	case o.symbol != nil:
+		typ = o.symbol.resolveType(p)
+	case o.callee != "":
+		typ = p.resultType(o.callee, o.result)
+	case o.container != nil:
+		typ = o.container.resolve(p)
+	case o.receiver != nil:
+		receiverType := strings.TrimPrefix(o.receiver.resolve(p), "*")
+		if receiverType == "" {
+			return ""
+		}
+
+		if o.method != "" {
+			typ = p.resultType(receiverType+"."+o.method, o.result)
+		} else {
+			typ = p.fieldType(receiverType, o.field)
+		}
+	}
+
+	if o.element {
+		typ = elementType(typ)
+	} else if o.key {
+		typ = keyType(typ)
+	}
+
+	return
+}
+
+// resultType returns type of the index-th result of function. When identifier is a structure, it is a conversion.
+func (p *Parser) resultType(identifier string, index int) string {
+	if fn, ok := p.functionsByName[identifier]; ok {
+		if index >= len(fn.Returns) {
+			return ""
+		}
+
+		pkg := fn.Package
+		if fn.ImportPath != "" {
+			pkg = fn.ImportPath
+		}
+
+		return qualifyType(pkg, p.importTable[fn.Path], fn.Returns[index].Type)
+	}
+
+	if _, ok := p.structureTypes[identifier]; ok && index == 0 {
+		return identifier
+	}
+
+	return ""
+}
+
+func (p *Parser) fieldType(structure, field string) string {
+	strct, ok := p.structureTypes[structure]
+	if !ok {
+		return ""
+	}
+
+	pkg := strct.PkgName
+	if strct.ImportPath != "" {
+		pkg = strct.ImportPath
+	}
+
+	for _, prm := range strct.Parameters {
+		if prm.Name == field {
+			return qualifyType(pkg, p.importTable[strct.File], prm.Type)
+		}
+	}
+
+	return ""
+}
+
+// qualifyType replaces package name of typ into import path, like "util.Server" into "example.com/sample/util.Server".
+// pkg is identity of package where typ is written, and imports is import table of the file.
+func qualifyType(pkg string, imports map[string]Import, typ string) string {
+	switch {
+	case typ == "":
+		return ""
+	case strings.HasPrefix(typ, "*"):
+		return qualifyType(pkg, imports, typ[1:])
+	case strings.HasPrefix(typ, "["):
+		index := strings.Index(typ, "]")
+		return typ[:index+1] + qualifyType(pkg, imports, typ[index+1:])
+	case strings.HasPrefix(typ, "map["):
+		key, value := splitMapType(typ)
+		return "map[" + qualifyType(pkg, imports, key) + "]" + qualifyType(pkg, imports, value)
+	case strings.HasPrefix(typ, "<-chan "), strings.HasPrefix(typ, "chan<- "), strings.HasPrefix(typ, "chan "):
+		index := strings.Index(typ, " ")
+		return typ[:index+1] + qualifyType(pkg, imports, strings.TrimSpace(typ[index+1:]))
+	case strings.HasPrefix(typ, "func"), strings.HasPrefix(typ, "struct"), strings.HasPrefix(typ, "interface"):
+		return typ
+	}
+
+	// instance of generic type, like List[int]
+	if index := strings.Index(typ, "["); index > 0 && strings.HasSuffix(typ, "]") {
+		typeArgs := splitTypeArgs(typ[index+1 : len(typ)-1])
+		for i, typeArg := range typeArgs {
+			typeArgs[i] = qualifyType(pkg, imports, typeArg)
+		}
+
+		return qualifyType(pkg, imports, typ[:index]) + "[" + strings.Join(typeArgs, ", ") + "]"
+	}
+
+	if index := strings.LastIndex(typ, "."); index >= 0 {
+		if imp, ok := imports[typ[:index]]; ok {
+			return imp.Path + typ[index:]
+		}
+
+		return typ
+	}
+
+	if types.Universe.Lookup(typ) != nil {
+		return typ
+	}
+
+	return pkg + "." + typ
+}
+
+// splitMapType splits "map[K]V" into K and V.
+func splitMapType(typ string) (key, value string) {
+	depth := 0
+	for index := len("map"); index < len(typ); index++ {
+		switch typ[index] {
+		case '[':
+			depth++
+		case ']':
+			depth--
+			if depth == 0 {
+				return typ[len("map["):index], typ[index+1:]
+			}
+		}
+	}
+
+	return "", ""
+}
+
+func elementType(typ string) string {
+	switch {
+	case strings.HasPrefix(typ, "["):
+		return typ[strings.Index(typ, "]")+1:]
+	case strings.HasPrefix(typ, "map["):
+		_, value := splitMapType(typ)
+		return value
+	case strings.HasPrefix(typ, "chan"):
+		return strings.TrimSpace(strings.TrimPrefix(strings.TrimPrefix(typ, "chan"), "<-"))
+	case strings.HasPrefix(typ, "<-chan"):
+		return strings.TrimSpace(strings.TrimPrefix(typ, "<-chan"))
+	case typ == "string":
+		return "rune"
+	}
+
+	return ""
+}
+
+// keyType returns type of the first variable of range over typ. It is element of channel,
+// and it is empty when typ is unknown or can not be ranged over.
+func keyType(typ string) string {
+	switch {
+	case strings.HasPrefix(typ, "map["):
+		key, _ := splitMapType(typ)
+		return key
+	case strings.HasPrefix(typ, "chan"), strings.HasPrefix(typ, "<-chan"):
+		return elementType(typ)
+	case strings.HasPrefix(typ, "["), typ == "string":
+		return "int"
+	}
+
+	// range over integer yields values of the same type
+	if obj, ok := types.Universe.Lookup(typ).(*types.TypeName); ok {
+		if basic, ok := obj.Type().(*types.Basic); ok && basic.Info()&types.IsInteger != 0 {
+			return typ
+		}
+	}
+
+	return ""
+}
+
+// scopeBuilder builds symbol tables while inspector walks through a package.
+type scopeBuilder struct {
+	p          *Parser
+	pkgName    string
+	importPath string
+	imports    map[string]Import
+	scope      *SymbolTable
+}
+
+func (b *scopeBuilder) pkg() string {
+	if b.importPath != "" {
+		return b.importPath
+	}
+
+	return b.pkgName
+}
+
+// enter opens a new scope for node, and records symbols that node declares.
+// parent is the node which contains node.
+func (b *scopeBuilder) enter(node, parent ast.Node) {
+	switch x := node.(type) {
+	case *ast.File:
+		b.scope = NewSymbolTable(b.scope, FileScope, x)
+	case *ast.FuncDecl:
+		b.scope = NewSymbolTable(b.scope, FunctionScope, x)
+		b.insertFields(x.Recv, ReceiverSymbol)
+		b.insertFields(x.Type.Params, ParameterSymbol)
+		b.insertFields(x.Type.Results, ResultSymbol)
+	case *ast.FuncLit:
+		b.scope = NewSymbolTable(b.scope, ClosureScope, x)
+		b.insertFields(x.Type.Params, ParameterSymbol)
+		b.insertFields(x.Type.Results, ResultSymbol)
+	case *ast.BlockStmt:
+		// parameters and body of function are in the same scope
+		switch parent.(type) {
+		case *ast.FuncDecl, *ast.FuncLit:
+			return
+		}
+		b.scope = NewSymbolTable(b.scope, BlockScope, x)
+	case *ast.IfStmt, *ast.ForStmt, *ast.SwitchStmt, *ast.TypeSwitchStmt, *ast.SelectStmt, *ast.CaseClause, *ast.CommClause:
+		b.scope = NewSymbolTable(b.scope, BlockScope, x)
+	case *ast.RangeStmt:
+		b.scope = NewSymbolTable(b.scope, BlockScope, x)
+		if x.Tok != token.DEFINE {
+			return
+		}
+
+		if key, ok := x.Key.(*ast.Ident); ok {
+			b.insert(key, &typeOrigin{container: b.origin(x.X, 0), key: true})
+		}
+		if value, ok := x.Value.(*ast.Ident); ok {
+			b.insert(value, &typeOrigin{container: b.origin(x.X, 0), element: true})
+		}
+	case *ast.AssignStmt:
+		if x.Tok != token.DEFINE {
+			return
+		}
+
+		b.insertValues(x.Lhs, nil, x.Rhs)
+	case *ast.ValueSpec:
+		lhs := make([]ast.Expr, 0, len(x.Names))
+		for _, name := range x.Names {
+			lhs = append(lhs, name)
+		}
+
+		scope := b.scope
+		if scope.Kind == FileScope {
+			// variables of top level are declared in package scope
+			b.scope = scope.Parent
+		}
+		b.insertValues(lhs, x.Type, x.Values)
+		b.scope = scope
+	}
+}
+
+// leave closes scope of node, if node opened it.
+func (b *scopeBuilder) leave(node ast.Node) {
+	if b.scope != nil && b.scope.Node == node && b.scope.Kind != PackageScope {
+		b.scope = b.scope.Parent
+	}
+}
+
+func (b *scopeBuilder) insertFields(fields *ast.FieldList, kind SymbolKind) {
+	if fields == nil {
+		return
+	}
+
+	for _, field := range fields.List {
+		_, isPointer := field.Type.(*ast.StarExpr)
+		typ := b.typeName(field.Type)
+
+		for _, name := range field.Names {
+			b.scope.Insert(&Symbol{
+				Name:      name.Name,
+				Kind:      kind,
+				Type:      typ,
+				IsPointer: isPointer,
+				Pos:       name.Pos(),
+			})
+		}
+	}
+}
+
+func (b *scopeBuilder) insertValues(lhs []ast.Expr, typ ast.Expr, rhs []ast.Expr) {
+	for index, l := range lhs {
+		ident, ok := l.(*ast.Ident)
+		if !ok {
+			continue
+		}
+
+		var origin *typeOrigin
+		switch {
+		case typ != nil:
+			origin = &typeOrigin{typ: b.typeName(typ)}
+		case len(lhs) == len(rhs):
+			origin = b.origin(rhs[index], 0)
+		case len(rhs) == 1:
+			origin = b.origin(rhs[0], index)
+		}
+
+		b.insert(ident, origin)
+	}
+}
+
+func (b *scopeBuilder) insert(ident *ast.Ident, origin *typeOrigin) {
+	symbol := &Symbol{
+		Name:   ident.Name,
+		Kind:   VariableSymbol,
+		Pos:    ident.Pos(),
+		origin: origin,
+	}
+
+	if origin != nil && origin.typ != "" {
+		symbol.Type = origin.typ
+		symbol.origin = nil
+	}
+
+	b.scope.Insert(symbol)
+}
+
+func (b *scopeBuilder) typeName(expr ast.Expr) string {
+	return qualifyType(b.pkg(), b.imports, b.p.ParseTyp(expr).String())
+}
+
+// origin returns where type of expr comes from. result is index of result, when expr is a function call.
+func (b *scopeBuilder) origin(expr ast.Expr, result int) *typeOrigin {
+	switch x := expr.(type) {
+	case *ast.Ident:
+		if symbol, ok := b.scope.Lookup(x.Name); ok {
+			return &typeOrigin{symbol: symbol}
+		}
+	case *ast.ParenExpr:
+		return b.origin(x.X, result)
+	case *ast.StarExpr:
+		return b.origin(x.X, result)
+	case *ast.UnaryExpr:
+		if x.Op == token.AND {
+			return b.origin(x.X, result)
+		}
+	case *ast.CompositeLit:
+		if x.Type != nil {
+			return &typeOrigin{typ: b.typeName(x.Type)}
+		}
+	case *ast.TypeAssertExpr:
+		if x.Type != nil && result == 0 {
+			return &typeOrigin{typ: b.typeName(x.Type)}
+		}
+	case *ast.IndexExpr:
+		if result == 0 {
+			return &typeOrigin{container: b.origin(x.X, 0), element: true}
+		}
+	case *ast.SelectorExpr:
+		if receiver := b.origin(x.X, 0); receiver != nil {
+			return &typeOrigin{receiver: receiver, field: x.Sel.Name}
+		}
+	case *ast.CallExpr:
+		if ident, ok := x.Fun.(*ast.Ident); ok && ident.Name == "new" && len(x.Args) == 1 {
+			return &typeOrigin{typ: b.typeName(x.Args[0])}
+		}
+
+		return b.callOrigin(x.Fun, result)
+	}
+
+	return nil
+}
+
+// callOrigin returns where result of calling fun comes from.
+func (b *scopeBuilder) callOrigin(fun ast.Expr, result int) *typeOrigin {
+	if generic, _, ok := instantiation(fun); ok {
+		fun = generic
+	}
+
+	if callee := calleeIdentifier(b.pkgName, b.importPath, b.imports, b.scope, fun); callee != "" {
+		return &typeOrigin{callee: callee, result: result}
+	}
+
+	if sel, ok := fun.(*ast.SelectorExpr); ok {
+		if receiver := b.origin(sel.X, 0); receiver != nil {
+			return &typeOrigin{receiver: receiver, method: sel.Sel.Name, result: result}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,386 @@
+package analyzer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSymbolTable_Lookup(t *testing.T) {
+	pkg := NewSymbolTable(nil, PackageScope, nil)
+	pkg.Insert(&Symbol{Name: "a", Type: "int"})
+
+	function := NewSymbolTable(pkg, FunctionScope, nil)
+	function.Insert(&Symbol{Name: "b", Type: "string"})
+	function.Insert(&Symbol{Name: "_", Type: "string"})
+
+	block := NewSymbolTable(function, BlockScope, nil)
+	block.Insert(&Symbol{Name: "a", Type: "float64"})
+
+	symbol, ok := block.Lookup("a")
+	assert.True(t, ok)
+	assert.Equal(t, "float64", symbol.Type)
+
+	symbol, ok = function.Lookup("a")
+	assert.True(t, ok)
+	assert.Equal(t, "int", symbol.Type)
+
+	_, ok = pkg.Lookup("b")
+	assert.False(t, ok)
+
+	_, ok = block.Lookup("_")
+	assert.False(t, ok)
+
+	assert.Equal(t, []*SymbolTable{function}, pkg.Children)
+}
+
+func TestQualifyType(t *testing.T) {
+	imports := map[string]Import{
+		"util": {Name: "util", Path: "example.com/sample/util"},
+	}
+
+	tests := []struct {
+		typ  string
+		want string
+	}{
+		{typ: "int", want: "int"},
+		{typ: "error", want: "error"},
+		{typ: "Server", want: "example.com/sample.Server"},
+		{typ: "*Server", want: "example.com/sample.Server"},
+		{typ: "util.Server", want: "example.com/sample/util.Server"},
+		{typ: "http.Handler", want: "http.Handler"},
+		{typ: "[]util.Server", want: "[]example.com/sample/util.Server"},
+		{typ: "map[string]Server", want: "map[string]example.com/sample.Server"},
+		{typ: "map[[2]int]util.Server", want: "map[[2]int]example.com/sample/util.Server"},
+		{typ: "chan *Server", want: "chan example.com/sample.Server"},
+		{typ: "<-chan util.Server", want: "<-chan example.com/sample/util.Server"},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, qualifyType("example.com/sample", imports, tt.typ), tt.typ)
+	}
+}
+
+func TestKeyType(t *testing.T) {
+	tests := []struct {
+		typ  string
+		want string
+	}{
+		{typ: "[]example.com/sample.Job", want: "int"},
+		{typ: "[3]string", want: "int"},
+		{typ: "string", want: "int"},
+		{typ: "int64", want: "int64"},
+		{typ: "map[string]example.com/sample.Job", want: "string"},
+		{typ: "chan example.com/sample.Job", want: "example.com/sample.Job"},
+		{typ: "<-chan example.com/sample.Job", want: "example.com/sample.Job"},
+		{typ: "example.com/sample.Jobs", want: ""},
+		{typ: "", want: ""},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, keyType(tt.typ), tt.typ)
+	}
+}
+
+func TestParser_RangeSymbols(t *testing.T) {
+	tests := []struct {
+		name  string
+		param string
+		body  string
+		want  map[string]string
+	}{{
+		name:  "채널을 range하는 경우",
+		param: "ch chan *Job",
+		body:  "for j := range ch {\n\t\tj.Run()\n\t}",
+		want:  map[string]string{"j": "example.com/sample.Job"},
+	}, {
+		name:  "수신 전용 채널을 range하는 경우",
+		param: "ch <-chan Job",
+		body:  "for j := range ch {\n\t\tj.Run()\n\t}",
+		want:  map[string]string{"j": "example.com/sample.Job"},
+	}, {
+		name:  "문자열을 range하는 경우",
+		param: "s string",
+		body:  "for i, r := range s {\n\t\t_, _ = i, r\n\t}",
+		want:  map[string]string{"i": "int", "r": "rune"},
+	}, {
+		name:  "타입을 모르는 값을 range하는 경우",
+		param: "jobs Jobs",
+		body:  "for i := range jobs {\n\t\t_ = i\n\t}",
+		want:  map[string]string{"i": ""},
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := writeModule(t, map[string]string{
+				"go.mod": "module example.com/sample\n",
+				"main.go": `package main
+
+type Job struct{}
+
+func (j *Job) Run() {}
+
+func work(` + tt.param + `) {
+	` + tt.body + `
+}
+`,
+			})
+
+			p := NewParser(root)
+			p.SetRecursive(true)
+			assert.NoError(t, p.Parse())
+
+			pkg, ok := p.SymbolTable("example.com/sample")
+			if !assert.True(t, ok) {
+				return
+			}
+
+			// package -> file -> work, which is declared after Run -> range
+			scope := pkg.Children[0].Children[1].Children[0]
+			for name, typ := range tt.want {
+				symbol, ok := scope.Lookup(name)
+				if assert.True(t, ok, name) {
+					assert.Equal(t, typ, symbol.Type, name)
+				}
+			}
+
+			for _, call := range p.FuncCalls() {
+				assert.NotEqual(t, "int.Run", call.Identifier())
+			}
+		})
+	}
+}
+
+func TestParser_ParseSymbolTable(t *testing.T) {
+	root := writeModule(t, map[string]string{
+		"go.mod": "module example.com/sample\n",
+		"main.go": `package main
+
+import "example.com/sample/util"
+
+var global = util.NewServer()
+
+func main() {
+	x := util.NewServer()
+	x.Start()
+
+	y := x
+	y.Stop()
+
+	cfg := x.Config
+	cfg.Load()
+
+	for _, s := range util.Servers() {
+		s.Start()
+	}
+
+	var z util.Server
+	z.Stop()
+
+	handle := func(h *util.Server) {
+		h.Start()
+	}
+	handle(x)
+
+	server, err := util.Open()
+	server.Stop()
+	_ = err
+
+	global.Start()
+}
+`,
+		"util/util.go": `package util
+
+type Config struct{}
+
+func (c Config) Load() {}
+
+type Server struct {
+	Config Config
+}
+
+func NewServer() *Server {
+	return &Server{}
+}
+
+func Open() (*Server, error) {
+	return nil, nil
+}
+
+func Servers() []*Server {
+	return nil
+}
+
+func (s *Server) Start() {}
+
+func (s *Server) Stop() {}
+`,
+	})
+
+	p := NewParser(root)
+	p.SetRecursive(true)
+	assert.NoError(t, p.Parse())
+
+	start, ok := p.Function("example.com/sample/util.Server.Start")
+	if assert.True(t, ok) {
+		assert.Len(t, start.Calls, 4)
+	}
+
+	stop, ok := p.Function("example.com/sample/util.Server.Stop")
+	if assert.True(t, ok) {
+		assert.Len(t, stop.Calls, 3)
+	}
+
+	load, ok := p.Function("example.com/sample/util.Config.Load")
+	if assert.True(t, ok) {
+		assert.Len(t, load.Calls, 1)
+	}
+
+	pkg, ok := p.SymbolTable("example.com/sample")
+	if !assert.True(t, ok) {
+		return
+	}
+
+	global, ok := pkg.Lookup("global")
+	if assert.True(t, ok) {
+		assert.Equal(t, "example.com/sample/util.Server", global.Type)
+	}
+
+	file := pkg.Children[0]
+	assert.Equal(t, FileScope, file.Kind)
+
+	function := file.Children[0]
+	assert.Equal(t, FunctionScope, function.Kind)
+
+	want := map[string]string{
+		"x":      "example.com/sample/util.Server",
+		"y":      "example.com/sample/util.Server",
+		"cfg":    "example.com/sample/util.Config",
+		"z":      "example.com/sample/util.Server",
+		"server": "example.com/sample/util.Server",
+		"err":    "error",
+	}
+	for name, typ := range want {
+		symbol, ok := function.Lookup(name)
+		if assert.True(t, ok, name) {
+			assert.Equal(t, typ, symbol.Type, name)
+		}
+	}
+}
+
+func TestParser_NestedCalls(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    string
+		callees []string
+	}{{
+		name:    "인자로 넘긴 함수 호출 결과",
+		body:    "fmt.Println(util.NewServer())",
+		callees: []string{"fmt.Println", "example.com/sample/util.NewServer"},
+	}, {
+		name:    "인자로 넘긴 클로저 안의 호출",
+		body:    `register("/", func(s *util.Server) { s.Start() })`,
+		callees: []string{"example.com/sample.register", "example.com/sample/util.Server.Start"},
+	}, {
+		name:    "go 문의 클로저 안의 호출",
+		body:    "go func() { s := util.NewServer(); s.Start() }()",
+		callees: []string{"func()()", "example.com/sample/util.NewServer", "example.com/sample/util.Server.Start"},
+	}, {
+		name:    "defer 문의 클로저 안의 호출",
+		body:    "defer func(s *util.Server) { s.Start() }(util.NewServer())",
+		callees: []string{"func(s *util.Server)()", "example.com/sample/util.Server.Start", "example.com/sample/util.NewServer"},
+	}, {
+		name:    "호출 결과의 메서드 호출",
+		body:    "util.NewServer().Start()",
+		callees: []string{"example.com/sample/util.Server.Start", "example.com/sample/util.NewServer"},
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := writeModule(t, map[string]string{
+				"go.mod": "module example.com/sample\n",
+				"main.go": `package main
+
+import (
+	"fmt"
+
+	"example.com/sample/util"
+)
+
+func register(pattern string, handler func(s *util.Server)) {}
+
+func main() {
+	` + tt.body + `
+}
+`,
+				"util/util.go": `package util
+
+type Server struct{}
+
+func NewServer() *Server {
+	return &Server{}
+}
+
+func (s *Server) Start() {}
+`,
+			})
+
+			p := NewParser(root)
+			p.SetRecursive(true)
+			assert.NoError(t, p.Parse())
+
+			var callees []string
+			for _, call := range p.FuncCalls() {
+				if call.Parent != nil && call.Parent.Name == "main" {
+					callees = append(callees, call.Identifier())
+				}
+			}
+			assert.Equal(t, tt.callees, callees)
+		})
+	}
+}
+
+func TestParser_ClosureScope(t *testing.T) {
+	root := writeModule(t, map[string]string{
+		"go.mod": "module example.com/sample\n",
+		"main.go": `package main
+
+import "net/http"
+
+func main() {
+	http.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		path := r.URL.Path
+		_ = path
+	})
+}
+`,
+	})
+
+	p := NewParser(root)
+	p.SetRecursive(true)
+	assert.NoError(t, p.Parse())
+
+	pkg, ok := p.SymbolTable("example.com/sample")
+	if !assert.True(t, ok) {
+		return
+	}
+
+	function := pkg.Children[0].Children[0]
+	if !assert.Len(t, function.Children, 1) {
+		return
+	}
+
+	closure := function.Children[0]
+	assert.Equal(t, ClosureScope, closure.Kind)
+
+	names := make([]string, 0)
+	for _, symbol := range closure.Symbols() {
+		names = append(names, symbol.Name)
+	}
+	assert.Equal(t, []string{"w", "r", "path"}, names)
+
+	r, ok := closure.Lookup("r")
+	if assert.True(t, ok) {
+		assert.Equal(t, "net/http.Request", r.Type)
+	}
+}
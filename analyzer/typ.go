@@ -0,0 +1,538 @@
+package analyzer
+
+import (
+	"fmt"
+	"go/ast"
+	"go/types"
+	"strconv"
+	"strings"
+)
+
+// TypeKind is kind of Typ.
+type TypeKind int
+
+const (
+	InvalidType TypeKind = iota
+	NamedType
+	PointerType
+	SliceType
+	ArrayType
+	MapType
+	ChanType
+	FuncType
+	StructType
+	InterfaceType
+	EllipsisType
+	InstanceType
+)
+
+var typeKindNames = []string{"invalid", "named", "pointer", "slice", "array", "map", "chan", "func", "struct", "interface", "ellipsis", "instance"}
+
+func (k TypeKind) String() string {
+	if int(k) < len(typeKindNames) {
+		return typeKindNames[k]
+	}
+
+	return "unknown"
+}
+
+func (k TypeKind) MarshalText() ([]byte, error) {
+	return []byte(k.String()), nil
+}
+
+func (k *TypeKind) UnmarshalText(text []byte) error {
+	for index, name := range typeKindNames {
+		if name == string(text) {
+			*k = TypeKind(index)
+			return nil
+		}
+	}
+
+	return fmt.Errorf("unknown type kind %q", text)
+}
+
+// Typ is a type expression in structured form.
+//
+//   - NamedType has Package and Name. Package is the qualifier as written, like http of http.Handler,
+//     and it is empty for predeclared and local types. Path is import path of the type, like net/http, which is
+//     resolved with imports of the file after parsing. It is empty for predeclared types.
+//   - PointerType, SliceType, ArrayType, ChanType and EllipsisType have Elem. ArrayType has Len, and ChanType has Dir.
+//   - MapType has Key and Elem.
+//   - FuncType has Params and Results.
+//   - StructType and InterfaceType have Fields. Methods of interface are fields with FuncType.
+//   - InstanceType is generic type with TypeArgs, and Elem is the generic type.
+type Typ struct {
+	Kind     TypeKind    `json:"kind"`
+	Package  string      `json:"package,omitempty"`
+	Path     string      `json:"path,omitempty"`
+	Name     string      `json:"name,omitempty"`
+	Len      string      `json:"len,omitempty"`
+	Dir      ast.ChanDir `json:"dir,omitempty"`
+	Elem     *Typ        `json:"elem,omitempty"`
+	Key      *Typ        `json:"key,omitempty"`
+	Params   []Typ       `json:"params,omitempty"`
+	Results  []Typ       `json:"results,omitempty"`
+	Fields   []TypeField `json:"fields,omitempty"`
+	TypeArgs []Typ       `json:"type_args,omitempty"`
+}
+
+// TypeField is a field of struct, or a method of interface. Embedded field has no name.
+type TypeField struct {
+	Name string `json:"name,omitempty"`
+	Type Typ    `json:"type"`
+}
+
+func (t Typ) String() string {
+	switch t.Kind {
+	case NamedType:
+		if t.Package != "" {
+			return t.Package + "." + t.Name
+		}
+		return t.Name
+	case PointerType:
+		return "*" + t.Elem.String()
+	case SliceType:
+		return "[]" + t.Elem.String()
+	case ArrayType:
+		return "[" + t.Len + "]" + t.Elem.String()
+	case EllipsisType:
+		return "..." + t.Elem.String()
+	case MapType:
+		return "map[" + t.Key.String() + "]" + t.Elem.String()
+	case ChanType:
+		switch t.Dir {
+		case ast.SEND:
+			return "chan<- " + t.Elem.String()
+		case ast.RECV:
+			return "<-chan " + t.Elem.String()
+		}
+		return "chan " + t.Elem.String()
+	case FuncType:
+		return "func" + t.signature()
+	case StructType:
+		fields := make([]string, 0, len(t.Fields))
+		for _, field := range t.Fields {
+			fields = append(fields, strings.TrimSpace(field.Name+" "+field.Type.String()))
+		}
+		return "struct{" + strings.Join(fields, "; ") + "}"
+	case InterfaceType:
+		fields := make([]string, 0, len(t.Fields))
+		for _, field := range t.Fields {
+			if field.Name == "" {
+				fields = append(fields, field.Type.String())
+				continue
+			}
+			fields = append(fields, field.Name+field.Type.signature())
+		}
+		return "interface{" + strings.Join(fields, "; ") + "}"
+	case InstanceType:
+		return t.Elem.String() + "[" + joinTyps(t.TypeArgs) + "]"
+	}
+
+	return t.Name
+}
+
+func (t Typ) signature() string {
+	s := "(" + joinTyps(t.Params) + ")"
+	switch len(t.Results) {
+	case 0:
+		return s
+	case 1:
+		return s + " " + t.Results[0].String()
+	}
+
+	return s + " (" + joinTyps(t.Results) + ")"
+}
+
+func joinTyps(ts []Typ) string {
+	ss := make([]string, 0, len(ts))
+	for _, t := range ts {
+		ss = append(ss, t.String())
+	}
+
+	return strings.Join(ss, ", ")
+}
+
+// Equal reports whether t and o are the same type expression. Named types are compared by Path when both are
+// resolved, so http.Handler of different imports are different.
+func (t Typ) Equal(o Typ) bool {
+	if t.Kind != o.Kind || t.Name != o.Name || t.Len != o.Len || t.Dir != o.Dir {
+		return false
+	}
+
+	if t.Path != "" && o.Path != "" {
+		if t.Path != o.Path {
+			return false
+		}
+	} else if t.Package != o.Package {
+		return false
+	}
+
+	if !equalTyp(t.Elem, o.Elem) || !equalTyp(t.Key, o.Key) {
+		return false
+	}
+
+	if !equalTyps(t.Params, o.Params) || !equalTyps(t.Results, o.Results) || !equalTyps(t.TypeArgs, o.TypeArgs) {
+		return false
+	}
+
+	if len(t.Fields) != len(o.Fields) {
+		return false
+	}
+	for index := range t.Fields {
+		if t.Fields[index].Name != o.Fields[index].Name || !t.Fields[index].Type.Equal(o.Fields[index].Type) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func equalTyp(a, b *Typ) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+
+	return a.Equal(*b)
+}
+
+func equalTyps(a, b []Typ) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for index := range a {
+		if !a[index].Equal(b[index]) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Compare orders types by kind, and then by string. It returns 0 when the types are equal.
+func (t Typ) Compare(o Typ) int {
+	if t.Kind != o.Kind {
+		if t.Kind < o.Kind {
+			return -1
+		}
+		return 1
+	}
+
+	return strings.Compare(t.String(), o.String())
+}
+
+// resolve returns copy of t, which Path of named types is resolved. pkg is import path of the package
+// that t is written in, and imports is import table of the file. typeParams are names of type parameters
+// in scope, which are not types of the package, so they are left unresolved.
+func (t Typ) resolve(pkg string, imports map[string]Import, typeParams map[string]bool) Typ {
+	return t.mapNamed(func(named Typ) Typ {
+		if imp, ok := imports[named.Package]; ok && named.Package != "" {
+			named.Path = imp.Path
+		} else if named.Package == "" && !named.IsPredeclared() && !typeParams[named.Name] {
+			named.Path = pkg
+		}
+
+		return named
+	})
+}
+
+// qualify returns copy of t, which Package of named types is import path.
+func (t Typ) qualify(pkg string, imports map[string]Import, typeParams map[string]bool) Typ {
+	return t.resolve(pkg, imports, typeParams).mapNamed(func(named Typ) Typ {
+		if named.Path != "" {
+			named.Package = named.Path
+		}
+
+		return named
+	})
+}
+
+// canonical returns copy of t, which predeclared aliases byte, rune and any are replaced with the types they
+// denote, so the same types are written the same.
+func (t Typ) canonical() Typ {
+	return t.mapNamed(func(named Typ) Typ {
+		if named.Package != "" || named.Path != "" {
+			return named
+		}
+
+		switch named.Name {
+		case "byte":
+			named.Name = "uint8"
+		case "rune":
+			named.Name = "int32"
+		case "any":
+			return Typ{Kind: InterfaceType}
+		}
+
+		return named
+	})
+}
+
+// mapNamed returns copy of t, which named types are replaced with f of them.
+func (t Typ) mapNamed(f func(named Typ) Typ) Typ {
+	if t.Kind == NamedType {
+		t = f(t)
+	}
+
+	if t.Elem != nil {
+		elem := t.Elem.mapNamed(f)
+		t.Elem = &elem
+	}
+	if t.Key != nil {
+		key := t.Key.mapNamed(f)
+		t.Key = &key
+	}
+
+	t.Params = mapNamedTyps(t.Params, f)
+	t.Results = mapNamedTyps(t.Results, f)
+	t.TypeArgs = mapNamedTyps(t.TypeArgs, f)
+
+	if t.Fields != nil {
+		fields := make([]TypeField, 0, len(t.Fields))
+		for _, field := range t.Fields {
+			fields = append(fields, TypeField{Name: field.Name, Type: field.Type.mapNamed(f)})
+		}
+		t.Fields = fields
+	}
+
+	return t
+}
+
+func mapNamedTyps(ts []Typ, f func(named Typ) Typ) []Typ {
+	if ts == nil {
+		return nil
+	}
+
+	mapped := make([]Typ, 0, len(ts))
+	for _, t := range ts {
+		mapped = append(mapped, t.mapNamed(f))
+	}
+
+	return mapped
+}
+
+// Deref returns element of pointer type, or t itself.
+func (t Typ) Deref() Typ {
+	for t.Kind == PointerType {
+		t = *t.Elem
+	}
+
+	return t
+}
+
+// IsPredeclared reports whether t is a predeclared type, like int or error.
+func (t Typ) IsPredeclared() bool {
+	if t.Kind != NamedType || t.Package != "" {
+		return false
+	}
+
+	_, ok := types.Universe.Lookup(t.Name).(*types.TypeName)
+	return ok
+}
+
+// ParseTyp parses type expression into structured form. Unsupported expressions are InvalidType,
+// with the expression as Name.
+func (p *Parser) ParseTyp(x ast.Expr) (t Typ) {
+	switch x2 := x.(type) {
+	case *ast.Ident:
+		return Typ{Kind: NamedType, Name: x2.Name}
+	case *ast.SelectorExpr:
+		if pkg, ok := x2.X.(*ast.Ident); ok {
+			return Typ{Kind: NamedType, Package: pkg.Name, Name: x2.Sel.Name}
+		}
+	case *ast.ParenExpr:
+		return p.ParseTyp(x2.X)
+	case *ast.StarExpr:
+		return p.elemTyp(PointerType, x2.X)
+	case *ast.Ellipsis:
+		return p.elemTyp(EllipsisType, x2.Elt)
+	case *ast.ArrayType:
+		if x2.Len == nil {
+			return p.elemTyp(SliceType, x2.Elt)
+		}
+
+		t = p.elemTyp(ArrayType, x2.Elt)
+		t.Len = types.ExprString(x2.Len)
+		return
+	case *ast.MapType:
+		t = p.elemTyp(MapType, x2.Value)
+		key := p.ParseTyp(x2.Key)
+		t.Key = &key
+		return
+	case *ast.ChanType:
+		t = p.elemTyp(ChanType, x2.Value)
+		t.Dir = x2.Dir
+		return
+	case *ast.FuncType:
+		return Typ{
+			Kind:    FuncType,
+			Params:  p.fieldTyps(x2.Params),
+			Results: p.fieldTyps(x2.Results),
+		}
+	case *ast.StructType:
+		return Typ{Kind: StructType, Fields: p.typeFields(x2.Fields)}
+	case *ast.InterfaceType:
+		return Typ{Kind: InterfaceType, Fields: p.typeFields(x2.Methods)}
+	case *ast.IndexExpr, *ast.IndexListExpr:
+		generic, indices, _ := instantiation(x2)
+		t = p.elemTyp(InstanceType, generic)
+		for _, index := range indices {
+			t.TypeArgs = append(t.TypeArgs, p.ParseTyp(index))
+		}
+		return
+	}
+
+	if x != nil {
+		t.Name = types.ExprString(x)
+	}
+
+	return
+}
+
+// typesTyp returns t of the type checker in structured form. Package of named types is import path,
+// like Typ.qualify.
+func typesTyp(t types.Type) Typ {
+	switch t := t.(type) {
+	case *types.Basic:
+		if t.Kind() == types.UnsafePointer {
+			return Typ{Kind: NamedType, Package: "unsafe", Path: "unsafe", Name: "Pointer"}
+		}
+		return Typ{Kind: NamedType, Name: t.Name()}
+	case *types.Named:
+		named := Typ{Kind: NamedType, Name: t.Obj().Name()}
+		if pkg := t.Obj().Pkg(); pkg != nil {
+			named.Package, named.Path = pkg.Path(), pkg.Path()
+		}
+
+		if t.TypeArgs().Len() == 0 {
+			return named
+		}
+
+		instance := Typ{Kind: InstanceType, Elem: &named}
+		for i := 0; i < t.TypeArgs().Len(); i++ {
+			instance.TypeArgs = append(instance.TypeArgs, typesTyp(t.TypeArgs().At(i)))
+		}
+		return instance
+	case *types.TypeParam:
+		return Typ{Kind: NamedType, Name: t.Obj().Name()}
+	case *types.Pointer:
+		return typesElemTyp(PointerType, t.Elem())
+	case *types.Slice:
+		return typesElemTyp(SliceType, t.Elem())
+	case *types.Array:
+		array := typesElemTyp(ArrayType, t.Elem())
+		array.Len = strconv.FormatInt(t.Len(), 10)
+		return array
+	case *types.Map:
+		m := typesElemTyp(MapType, t.Elem())
+		key := typesTyp(t.Key())
+		m.Key = &key
+		return m
+	case *types.Chan:
+		ch := typesElemTyp(ChanType, t.Elem())
+		switch t.Dir() {
+		case types.SendOnly:
+			ch.Dir = ast.SEND
+		case types.RecvOnly:
+			ch.Dir = ast.RECV
+		default:
+			ch.Dir = ast.SEND | ast.RECV
+		}
+		return ch
+	case *types.Signature:
+		return Typ{
+			Kind:    FuncType,
+			Params:  typesTyps(t.Params(), t.Variadic()),
+			Results: typesTyps(t.Results(), false),
+		}
+	case *types.Struct:
+		s := Typ{Kind: StructType}
+		for i := 0; i < t.NumFields(); i++ {
+			field := TypeField{Type: typesTyp(t.Field(i).Type())}
+			if !t.Field(i).Embedded() {
+				field.Name = t.Field(i).Name()
+			}
+			s.Fields = append(s.Fields, field)
+		}
+		return s
+	case *types.Interface:
+		iface := Typ{Kind: InterfaceType}
+		for i := 0; i < t.NumExplicitMethods(); i++ {
+			iface.Fields = append(iface.Fields, TypeField{Name: t.ExplicitMethod(i).Name(), Type: typesTyp(t.ExplicitMethod(i).Type())})
+		}
+		for i := 0; i < t.NumEmbeddeds(); i++ {
+			iface.Fields = append(iface.Fields, TypeField{Type: typesTyp(t.EmbeddedType(i))})
+		}
+		return iface
+	}
+
+	return Typ{Name: types.TypeString(t, func(pkg *types.Package) string {
+		return pkg.Path()
+	})}
+}
+
+func typesElemTyp(kind TypeKind, elem types.Type) Typ {
+	e := typesTyp(elem)
+	return Typ{Kind: kind, Elem: &e}
+}
+
+// typesTyps returns types of the tuple. When variadic is true, the last one is EllipsisType instead of SliceType.
+func typesTyps(tuple *types.Tuple, variadic bool) (ts []Typ) {
+	for i := 0; i < tuple.Len(); i++ {
+		t := typesTyp(tuple.At(i).Type())
+		if variadic && i == tuple.Len()-1 && t.Kind == SliceType {
+			t.Kind = EllipsisType
+		}
+		ts = append(ts, t)
+	}
+
+	return
+}
+
+func (p *Parser) elemTyp(kind TypeKind, elem ast.Expr) Typ {
+	e := p.ParseTyp(elem)
+	return Typ{Kind: kind, Elem: &e}
+}
+
+// fieldTyps returns a type for each name of fields, so func(a, b int) has two parameters.
+func (p *Parser) fieldTyps(fields *ast.FieldList) (ts []Typ) {
+	if fields == nil {
+		return
+	}
+
+	for _, field := range fields.List {
+		t := p.ParseTyp(field.Type)
+
+		names := len(field.Names)
+		if names == 0 {
+			names = 1
+		}
+		for i := 0; i < names; i++ {
+			ts = append(ts, t)
+		}
+	}
+
+	return
+}
+
+func (p *Parser) typeFields(fields *ast.FieldList) (tfs []TypeField) {
+	if fields == nil {
+		return
+	}
+
+	for _, field := range fields.List {
+		t := p.ParseTyp(field.Type)
+
+		if len(field.Names) == 0 {
+			tfs = append(tfs, TypeField{Type: t})
+			continue
+		}
+
+		for _, name := range field.Names {
+			tfs = append(tfs, TypeField{Name: name.Name, Type: t})
+		}
+	}
+
+	return
+}
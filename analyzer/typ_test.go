@@ -0,0 +1,244 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"go/parser"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func parseTyp(t *testing.T, expr string) Typ {
+	x, err := parser.ParseExpr(expr)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p := Parser{}
+	return p.ParseTyp(x)
+}
+
+func TestParser_ParseTyp(t *testing.T) {
+	tests := []struct {
+		name string
+		expr string
+		want Typ
+	}{{
+		name: "기본 타입인 경우",
+		expr: "int",
+		want: *named("int"),
+	}, {
+		name: "다른 패키지의 타입인 경우",
+		expr: "http.Handler",
+		want: *named("http.Handler"),
+	}, {
+		name: "이중 포인터인 경우",
+		expr: "**T",
+		want: *pointer(pointer(named("T"))),
+	}, {
+		name: "슬라이스의 포인터인 경우",
+		expr: "*[]T",
+		want: *pointer(&Typ{Kind: SliceType, Elem: named("T")}),
+	}, {
+		name: "길이가 상수인 배열인 경우",
+		expr: "[N * 2]byte",
+		want: Typ{Kind: ArrayType, Len: "N * 2", Elem: named("byte")},
+	}, {
+		name: "맵인 경우",
+		expr: "map[string][]int",
+		want: Typ{Kind: MapType, Key: named("string"), Elem: &Typ{Kind: SliceType, Elem: named("int")}},
+	}, {
+		name: "받기 전용 채널인 경우",
+		expr: "<-chan error",
+		want: Typ{Kind: ChanType, Dir: 2, Elem: named("error")},
+	}, {
+		name: "함수인 경우",
+		expr: "func(a, b int, opts ...string) (int, error)",
+		want: Typ{
+			Kind:    FuncType,
+			Params:  []Typ{*named("int"), *named("int"), {Kind: EllipsisType, Elem: named("string")}},
+			Results: []Typ{*named("int"), *named("error")},
+		},
+	}, {
+		name: "구조체인 경우",
+		expr: "struct{ Name string; io.Reader }",
+		want: Typ{Kind: StructType, Fields: []TypeField{
+			{Name: "Name", Type: *named("string")},
+			{Type: *named("io.Reader")},
+		}},
+	}, {
+		name: "인터페이스인 경우",
+		expr: "interface{ fmt.Stringer; Close() error }",
+		want: Typ{Kind: InterfaceType, Fields: []TypeField{
+			{Type: *named("fmt.Stringer")},
+			{Name: "Close", Type: Typ{Kind: FuncType, Results: []Typ{*named("error")}}},
+		}},
+	}, {
+		name: "제네릭 타입의 인스턴스인 경우",
+		expr: "Pair[string, util.List[int]]",
+		want: Typ{Kind: InstanceType, Elem: named("Pair"), TypeArgs: []Typ{
+			*named("string"),
+			{Kind: InstanceType, Elem: named("util.List"), TypeArgs: []Typ{*named("int")}},
+		}},
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := parseTyp(t, tt.expr)
+			assert.Equal(t, tt.want, got)
+			assert.True(t, tt.want.Equal(got))
+		})
+	}
+}
+
+func TestTyp_String(t *testing.T) {
+	for _, expr := range []string{
+		"int",
+		"*http.Request",
+		"**T",
+		"*[]T",
+		"[4]byte",
+		"map[string]*util.Server",
+		"chan<- int",
+		"<-chan int",
+		"chan struct{}",
+		"func()",
+		"func(int, ...string) error",
+		"func(int) (string, error)",
+		"struct{Name string; io.Reader}",
+		"interface{fmt.Stringer; Close() error}",
+		"Pair[string, util.List[int]]",
+	} {
+		assert.Equal(t, expr, parseTyp(t, expr).String())
+	}
+}
+
+func TestTyp_EqualCompare(t *testing.T) {
+	a := parseTyp(t, "map[string]*util.Server")
+
+	assert.True(t, a.Equal(parseTyp(t, "map[string]*util.Server")))
+	assert.False(t, a.Equal(parseTyp(t, "map[string]util.Server")))
+	assert.False(t, parseTyp(t, "[4]int").Equal(parseTyp(t, "[8]int")))
+	assert.False(t, parseTyp(t, "chan<- int").Equal(parseTyp(t, "chan int")))
+
+	assert.Equal(t, 0, a.Compare(parseTyp(t, "map[string]*util.Server")))
+	assert.Equal(t, -1, parseTyp(t, "int").Compare(parseTyp(t, "*int")))
+	assert.Equal(t, -1, parseTyp(t, "int").Compare(parseTyp(t, "string")))
+	assert.Equal(t, 1, parseTyp(t, "func()").Compare(parseTyp(t, "[]int")))
+
+	assert.Equal(t, parseTyp(t, "util.Server"), parseTyp(t, "**util.Server").Deref())
+	assert.True(t, parseTyp(t, "error").IsPredeclared())
+	assert.False(t, parseTyp(t, "Server").IsPredeclared())
+	assert.False(t, parseTyp(t, "util.Server").IsPredeclared())
+}
+
+func TestParser_ResolveTyp(t *testing.T) {
+	root := writeModule(t, map[string]string{
+		"go.mod": "module example.com/sample\n",
+		"main.go": `package main
+
+import cfg "example.com/sample/one"
+
+type Config struct{}
+
+func first(c *cfg.Config, local Config) {}
+`,
+		"other.go": `package main
+
+import cfg "example.com/sample/two"
+
+func second(c *cfg.Config) {}
+`,
+		"alias.go": `package main
+
+import conf "example.com/sample/one"
+
+func third(c *conf.Config) {}
+`,
+		"one/one.go": "package one\n\ntype Config struct{}\n",
+		"two/two.go": "package two\n\ntype Config struct{}\n",
+	})
+
+	p := NewParser(root)
+	p.SetRecursive(true)
+	assert.NoError(t, p.Parse())
+
+	param := func(id string, index int) Typ {
+		f, ok := p.Function(id)
+		if !assert.True(t, ok) || !assert.Greater(t, len(f.Parameters), index) {
+			return Typ{}
+		}
+		return *f.Parameters[index].Typ
+	}
+
+	first := param("example.com/sample.first", 0)
+	assert.Equal(t, "example.com/sample/one", first.Elem.Path)
+	assert.Equal(t, "example.com/sample", param("example.com/sample.first", 1).Path)
+
+	// cfg.Config of other imports is a different type, and conf.Config of the same import is the same type
+	assert.False(t, first.Equal(param("example.com/sample.second", 0)))
+	assert.True(t, first.Equal(param("example.com/sample.third", 0)))
+	assert.Equal(t, "*cfg.Config", first.String())
+}
+
+func TestParser_ResolveTypParams(t *testing.T) {
+	root := writeModule(t, map[string]string{
+		"go.mod": "module example.com/sample\n",
+		"main.go": `package main
+
+type T struct{}
+
+type List[E any] struct {
+	items []E
+}
+
+func (l *List[E]) Push(v E, t T) {}
+
+func Map[T any](xs []T, f func(T) Item) []Item {
+	return nil
+}
+
+type Item struct{}
+`,
+	})
+
+	p := NewParser(root)
+	p.SetRecursive(true)
+	assert.NoError(t, p.Parse())
+
+	// type parameters are not types of the package, even when the package has a type of the same name
+	mapFunc, ok := p.Function("example.com/sample.Map")
+	if assert.True(t, ok) {
+		xs := *mapFunc.Parameters[0].Typ
+		assert.Equal(t, Typ{Kind: NamedType, Name: "T"}, *xs.Elem)
+		assert.Equal(t, "example.com/sample", mapFunc.Parameters[1].Typ.Results[0].Path)
+		assert.Empty(t, mapFunc.Parameters[1].Typ.Params[0].Path)
+		assert.Equal(t, "example.com/sample", mapFunc.Returns[0].Typ.Elem.Path)
+	}
+
+	push, ok := p.Function("example.com/sample.List.Push")
+	if assert.True(t, ok) {
+		assert.Empty(t, push.Parameters[0].Typ.Path)
+		assert.Equal(t, "example.com/sample", push.Parameters[1].Typ.Path)
+	}
+
+	snapshot, ok := p.Snapshot().Function("example.com/sample.Map")
+	if assert.True(t, ok) {
+		b, err := json.Marshal(snapshot.Parameters[0].Typ)
+		assert.NoError(t, err)
+		assert.NotContains(t, string(b), `"path"`)
+	}
+}
+
+func TestTyp_JSON(t *testing.T) {
+	typ := parseTyp(t, "func(a map[string]*util.Server, c <-chan int) []Pair[int, string]")
+
+	b, err := json.Marshal(typ)
+	assert.NoError(t, err)
+	assert.Contains(t, string(b), `"kind":"func"`)
+
+	var loaded Typ
+	assert.NoError(t, json.Unmarshal(b, &loaded))
+	assert.True(t, typ.Equal(loaded))
+	assert.Equal(t, typ.String(), loaded.String())
+}
@@ -0,0 +1,326 @@
+package analyzer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/types"
+	"sort"
+)
+
+// SetTyped makes Parse type-check parsed packages with go/types. Calls, parameters and fields then have
+// types.Object and types.Type, and method calls are resolved by the type checker instead of the symbol tables.
+// Selectors of calls have them too, and so do selectors that are parsed with ParseSelector after Parse.
+// Syntactic analysis is still used for calls that the type checker can not resolve.
+func (p *Parser) SetTyped(typed bool) {
+	p.typed = typed
+}
+
+// TypesInfo returns result of type checking. It is nil unless the parser is typed.
+func (p Parser) TypesInfo() *types.Info {
+	return p.typesInfo
+}
+
+// TypesPackage returns type-checked package. pkg is import path, or package name when it is parsed out of a module.
+func (p Parser) TypesPackage(pkg string) (typesPackage *types.Package, ok bool) {
+	typesPackage, ok = p.typesPackages[pkg]
+	return
+}
+
+// keepSyntax keeps files of the package to type-check them after every package is parsed.
+func (p *Parser) keepSyntax(pkg string, files []*ast.File) {
+	if p.typed {
+		p.syntax[pkg] = files
+	}
+}
+
+var errImportCycle = errors.New("import cycle")
+
+// packageImporter type-checks parsed packages when they are imported, and imports others from source.
+type packageImporter struct {
+	p        *Parser
+	info     *types.Info
+	packages map[string]*types.Package
+	checking map[string]bool
+}
+
+func (imp *packageImporter) Import(path string) (*types.Package, error) {
+	if _, ok := imp.p.syntax[path]; ok {
+		return imp.check(path)
+	}
+
+	return imp.p.importer.Import(path)
+}
+
+func (imp *packageImporter) check(path string) (*types.Package, error) {
+	if pkg, ok := imp.packages[path]; ok {
+		return pkg, nil
+	}
+
+	if imp.checking[path] {
+		return nil, fmt.Errorf("%w: %s", errImportCycle, path)
+	}
+	imp.checking[path] = true
+
+	conf := types.Config{
+		Importer: imp,
+		Error: func(err error) {
+			if typeErr, ok := err.(types.Error); ok {
+				imp.p.diagnostics = append(imp.p.diagnostics, Diagnostic{
+					Severity: Warning,
+					Position: imp.p.fset.Position(typeErr.Pos),
+					NodeKind: "types.Error",
+					Message:  typeErr.Msg,
+				})
+			}
+		},
+	}
+
+	// package is returned even if it has errors, so the rest of the package is still typed
+	pkg, _ := conf.Check(path, imp.p.fset, imp.p.syntax[path], imp.info)
+	imp.packages[path] = pkg
+
+	return pkg, nil
+}
+
+// checkTypes type-checks every kept package, and attaches types to calls, functions and structures.
+func (p *Parser) checkTypes(ctx context.Context) error {
+	if !p.typed {
+		return nil
+	}
+
+	if p.importer == nil {
+		p.importer = newSourceImporter()
+	}
+
+	imp := &packageImporter{
+		p: p,
+		info: &types.Info{
+			Types:      make(map[ast.Expr]types.TypeAndValue),
+			Defs:       make(map[*ast.Ident]types.Object),
+			Uses:       make(map[*ast.Ident]types.Object),
+			Selections: make(map[*ast.SelectorExpr]*types.Selection),
+			Instances:  make(map[*ast.Ident]types.Instance),
+		},
+		packages: make(map[string]*types.Package),
+		checking: make(map[string]bool),
+	}
+
+	pkgs := make([]string, 0, len(p.syntax))
+	for pkg := range p.syntax {
+		pkgs = append(pkgs, pkg)
+	}
+	sort.Strings(pkgs)
+
+	for _, pkg := range pkgs {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		if _, err := imp.check(pkg); err != nil {
+			return err
+		}
+	}
+
+	p.typesInfo = imp.info
+	p.typesPackages = imp.packages
+
+	p.typeCalls()
+	p.typeSelectors()
+	p.typeFunctions()
+	p.typeStructures()
+
+	return nil
+}
+
+// typeCalls resolves callee of calls by the type checker.
+func (p *Parser) typeCalls() {
+	for index, call := range p.functionCalls {
+		if call.expr == nil {
+			continue
+		}
+
+		call.TypeInfo = p.typesInfo.TypeOf(call.expr.Fun)
+
+		fun := call.expr.Fun
+		if generic, _, ok := instantiation(fun); ok {
+			fun = generic
+		}
+
+		var ident *ast.Ident
+		switch fun := fun.(type) {
+		case *ast.Ident:
+			ident = fun
+		case *ast.SelectorExpr:
+			ident = fun.Sel
+			call.Selection = p.typesInfo.Selections[fun]
+		}
+
+		if ident != nil {
+			if instance, ok := p.typesInfo.Instances[ident]; ok {
+				call.TypeArgs = make([]string, 0, instance.TypeArgs.Len())
+				for i := 0; i < instance.TypeArgs.Len(); i++ {
+					call.TypeArgs = append(call.TypeArgs, instance.TypeArgs.At(i).String())
+				}
+			}
+
+			if fn, ok := p.typesInfo.Uses[ident].(*types.Func); ok {
+				call.Object = fn
+				if callee := funcIdentifier(fn); callee != "" {
+					call.Callee = callee
+					call.receiver = nil
+				}
+			}
+		}
+
+		p.functionCalls[index] = call
+	}
+}
+
+// funcIdentifier returns identifier of the function, same as FunctionStatement.Identifier.
+func funcIdentifier(fn *types.Func) string {
+	if fn.Pkg() == nil {
+		return ""
+	}
+
+	recv := fn.Type().(*types.Signature).Recv()
+	if recv == nil {
+		return fn.Pkg().Path() + "." + fn.Name()
+	}
+
+	typ := recv.Type()
+	if pointer, ok := typ.(*types.Pointer); ok {
+		typ = pointer.Elem()
+	}
+
+	named, ok := typ.(*types.Named)
+	if !ok {
+		return ""
+	}
+
+	return fn.Pkg().Path() + "." + named.Obj().Name() + "." + fn.Name()
+}
+
+// typeSelectors attaches selected objects to selectors of calls, and to selectors in their operands.
+func (p *Parser) typeSelectors() {
+	for _, call := range p.functionCalls {
+		if call.Selector != nil {
+			p.typeSelector(call.Selector)
+		}
+	}
+}
+
+// typeSelector attaches the selected object to s, when its expression is type-checked.
+// ParentType of syntactic mode is kept when the type checker does not know the selector.
+func (p Parser) typeSelector(s *Selector) {
+	if field, ok := s.Field.(Selector); ok {
+		p.typeSelector(&field)
+		s.Field = field
+	}
+
+	if p.typesInfo == nil || s.expr == nil {
+		return
+	}
+
+	if selection, ok := p.typesInfo.Selections[s.expr]; ok {
+		s.Selection = selection
+		s.Object = selection.Obj()
+	} else if obj, ok := p.typesInfo.Uses[s.expr.Sel]; ok {
+		s.Object = obj // qualified identifier, like fmt.Println
+	} else {
+		return
+	}
+
+	s.TypeInfo = s.Object.Type()
+
+	qualifier := func(pkg *types.Package) string {
+		return pkg.Path()
+	}
+
+	sig, ok := s.TypeInfo.(*types.Signature)
+	switch {
+	case !ok:
+		s.ParentType = types.TypeString(s.TypeInfo, qualifier)
+	case sig.Results().Len() == 1:
+		s.ParentType = types.TypeString(sig.Results().At(0).Type(), qualifier)
+	case sig.Results().Len() > 1:
+		s.ParentType = types.TypeString(sig.Results(), qualifier)
+	default:
+		s.ParentType = ""
+	}
+}
+
+// typeFunctions attaches objects of receiver, parameters and returns.
+func (p *Parser) typeFunctions() {
+	for _, f := range p.functionsByName {
+		decl, ok := f.Node.(*ast.FuncDecl)
+		if !ok {
+			continue
+		}
+
+		if decl.Recv != nil {
+			receiver := Parameters{f.Receiver}
+			p.typeParameters(decl.Recv, receiver)
+			f.Receiver = receiver[0]
+		}
+
+		p.typeParameters(decl.Type.Params, f.Parameters)
+		p.typeParameters(decl.Type.Results, f.Returns)
+	}
+}
+
+// typeParameters attaches objects of fields in order. Parameters are parsed in the same order of fields,
+// and a field without name is a parameter.
+func (p *Parser) typeParameters(fields *ast.FieldList, parameters Parameters) {
+	if fields == nil {
+		return
+	}
+
+	index := 0
+	for _, field := range fields.List {
+		names := len(field.Names)
+		if names == 0 {
+			names = 1
+		}
+
+		for i := 0; i < names && index < len(parameters); i++ {
+			parameters[index].TypeInfo = p.typesInfo.TypeOf(field.Type)
+			if len(field.Names) != 0 {
+				parameters[index].Object = p.typesInfo.Defs[field.Names[i]]
+			}
+			index++
+		}
+	}
+}
+
+// typeStructures attaches fields of type-checked structures.
+func (p *Parser) typeStructures() {
+	for id, strct := range p.structureTypes {
+		pkg := strct.PkgName
+		if strct.ImportPath != "" {
+			pkg = strct.ImportPath
+		}
+
+		typesPackage, ok := p.typesPackages[pkg]
+		if !ok || typesPackage == nil {
+			continue
+		}
+
+		obj := typesPackage.Scope().Lookup(strct.Name)
+		if obj == nil {
+			continue
+		}
+
+		s, ok := obj.Type().Underlying().(*types.Struct)
+		if !ok || s.NumFields() != len(strct.Parameters) {
+			continue
+		}
+
+		for index := range strct.Parameters {
+			strct.Parameters[index].Object = s.Field(index)
+			strct.Parameters[index].TypeInfo = s.Field(index).Type()
+		}
+		p.structureTypes[id] = strct
+	}
+}
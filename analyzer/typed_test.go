@@ -0,0 +1,261 @@
+package analyzer
+
+import (
+	"bytes"
+	"go/ast"
+	"go/types"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParser_SetTyped(t *testing.T) {
+	root := writeModule(t, map[string]string{
+		"go.mod": "module example.com/sample\n",
+		"main.go": `package main
+
+import (
+	"strings"
+
+	"example.com/sample/util"
+)
+
+type Static struct {
+	util.Base
+}
+
+func main() {
+	s := Static{}
+	s.Name()
+
+	x := util.NewServer()
+	name := strings.ToUpper("a")
+	x.Start(name)
+}
+`,
+		"util/util.go": `package util
+
+type Base struct {
+	name string
+}
+
+func (b Base) Name() string {
+	return b.name
+}
+
+type Server struct {
+	Base
+	Port int
+}
+
+func NewServer() *Server {
+	return &Server{}
+}
+
+func (s *Server) Start(name string) {}
+`,
+	})
+
+	parse := func(typed bool) Parser {
+		p := NewParser(root)
+		p.SetRecursive(true)
+		p.SetTyped(typed)
+		assert.NoError(t, p.Parse())
+
+		return p
+	}
+
+	t.Run("임베딩된 타입의 메소드를 호출하는 경우", func(t *testing.T) {
+		name, ok := parse(false).Function("example.com/sample/util.Base.Name")
+		if assert.True(t, ok) {
+			assert.Empty(t, name.Calls)
+		}
+
+		p := parse(true)
+		name, ok = p.Function("example.com/sample/util.Base.Name")
+		if assert.True(t, ok) && assert.Len(t, name.Calls, 1) {
+			call := name.Calls[0]
+			assert.Equal(t, "example.com/sample/util.Base.Name", call.Callee)
+			assert.Equal(t, "Name", call.Object.Name())
+			if assert.NotNil(t, call.Selection) {
+				assert.Equal(t, types.MethodVal, call.Selection.Kind())
+				assert.Equal(t, "example.com/sample.Static", call.Selection.Recv().String())
+			}
+		}
+	})
+
+	t.Run("파라미터와 필드에 타입이 있는 경우", func(t *testing.T) {
+		p := parse(true)
+
+		start, ok := p.Function("example.com/sample/util.Server.Start")
+		if assert.True(t, ok) {
+			assert.Equal(t, "*example.com/sample/util.Server", start.Receiver.TypeInfo.String())
+			assert.Equal(t, "s", start.Receiver.Object.Name())
+			assert.Equal(t, "string", start.Parameters[0].TypeInfo.String())
+			assert.Len(t, start.Calls, 1)
+		}
+
+		toUpper := false
+		for _, call := range p.FuncCalls() {
+			if call.Callee == "strings.ToUpper" {
+				toUpper = true
+				assert.Equal(t, "func(s string) string", call.TypeInfo.String())
+			}
+		}
+		assert.True(t, toUpper)
+
+		for _, strct := range p.Structures() {
+			if strct.Name == "Server" {
+				assert.Equal(t, "example.com/sample/util.Base", strct.Parameters[0].TypeInfo.String())
+				assert.Equal(t, "int", strct.Parameters[1].TypeInfo.String())
+			}
+		}
+
+		pkg, ok := p.TypesPackage("example.com/sample/util")
+		if assert.True(t, ok) {
+			assert.NotNil(t, pkg.Scope().Lookup("Server"))
+		}
+		assert.NotNil(t, p.TypesInfo())
+	})
+
+	t.Run("셀렉터에 타입이 있는 경우", func(t *testing.T) {
+		p := parse(true)
+
+		selector := func(pkg, expr string) (s Selector) {
+			for _, file := range p.syntax[pkg] {
+				ast.Inspect(file, func(n ast.Node) bool {
+					if x, ok := n.(*ast.SelectorExpr); ok && types.ExprString(x) == expr {
+						s = p.ParseSelector(file.Name.Name, x)
+					}
+					return true
+				})
+			}
+			return
+		}
+
+		tests := []struct {
+			name       string
+			pkg        string
+			expr       string
+			object     string
+			parentType string
+			selected   bool
+			kind       types.SelectionKind
+		}{{
+			name:       "필드",
+			pkg:        "example.com/sample/util",
+			expr:       "b.name",
+			object:     "field name string",
+			parentType: "string",
+			selected:   true,
+			kind:       types.FieldVal,
+		}, {
+			name:       "메소드",
+			pkg:        "example.com/sample",
+			expr:       "x.Start",
+			object:     "func (*example.com/sample/util.Server).Start(name string)",
+			parentType: "",
+			selected:   true,
+			kind:       types.MethodVal,
+		}, {
+			name:       "패키지의 함수",
+			pkg:        "example.com/sample",
+			expr:       "strings.ToUpper",
+			object:     "func strings.ToUpper(s string) string",
+			parentType: "string",
+		}}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				s := selector(tt.pkg, tt.expr)
+				if !assert.NotNil(t, s.Object) {
+					return
+				}
+
+				assert.Equal(t, tt.object, s.Object.String())
+				assert.Equal(t, s.Object.Type(), s.TypeInfo)
+				assert.Equal(t, tt.parentType, s.ParentType)
+				if !tt.selected {
+					assert.Nil(t, s.Selection)
+				} else if assert.NotNil(t, s.Selection) {
+					assert.Equal(t, tt.kind, s.Selection.Kind())
+				}
+			})
+		}
+
+		// selectors of calls are typed by Parse
+		selectors := make(map[string]*Selector)
+		for _, call := range p.FuncCalls() {
+			if call.Selector != nil {
+				selectors[call.Name] = call.Selector
+			}
+		}
+
+		if toUpper := selectors["strings.ToUpper"]; assert.NotNil(t, toUpper) {
+			assert.Equal(t, "func strings.ToUpper(s string) string", toUpper.Object.String())
+			assert.Equal(t, "string", toUpper.ParentType)
+		}
+		if newServer := selectors["util.NewServer"]; assert.NotNil(t, newServer) {
+			assert.Equal(t, "*example.com/sample/util.Server", newServer.ParentType)
+		}
+		if start := selectors["x.Start"]; assert.NotNil(t, start) && assert.NotNil(t, start.Selection) {
+			assert.Equal(t, types.MethodVal, start.Selection.Kind())
+		}
+
+		// syntactic mode does not know types
+		untyped := parse(false)
+		s := untyped.ParseSelector("util", &ast.SelectorExpr{X: ast.NewIdent("b"), Sel: ast.NewIdent("name")})
+		assert.Nil(t, s.Object)
+		assert.Empty(t, s.ParentType)
+
+		// results of functions that are parsed out of a module are known without the type checker
+		open := &FunctionStatement{Name: "Open", Returns: Parameters{{Type: "Server", IsPointer: true}, {Type: "error"}}}
+		untyped.functionsByName["util.Open"] = open
+		s = untyped.ParseSelector("main", &ast.SelectorExpr{X: ast.NewIdent("util"), Sel: ast.NewIdent("Open")})
+		assert.Nil(t, s.Object)
+		assert.Equal(t, open.Returns.String(), s.ParentType)
+	})
+
+	t.Run("타입 정보는 스냅샷에 저장되지 않는 경우", func(t *testing.T) {
+		p := parse(true)
+
+		var buf bytes.Buffer
+		assert.NoError(t, p.Save(&buf))
+
+		loaded, err := Load(&buf)
+		assert.NoError(t, err)
+		assert.Equal(t, p.Snapshot(), loaded)
+	})
+}
+
+func TestParser_SetTypedError(t *testing.T) {
+	root := writeModule(t, map[string]string{
+		"go.mod": "module example.com/sample\n",
+		"main.go": `package main
+
+func main() {
+	run(unknown)
+}
+
+func run(a int) {}
+`,
+	})
+
+	p := NewParser(root)
+	p.SetTyped(true)
+	assert.NoError(t, p.Parse())
+
+	run, ok := p.Function("example.com/sample.run")
+	if assert.True(t, ok) {
+		assert.Len(t, run.Calls, 1)
+	}
+
+	diagnostics := p.Diagnostics().Filter(func(d Diagnostic) bool {
+		return d.NodeKind == "types.Error"
+	})
+	if assert.Len(t, diagnostics, 1) {
+		assert.Equal(t, filepath.Join(root, "main.go"), diagnostics[0].Position.Filename)
+		assert.Contains(t, diagnostics[0].Message, "unknown")
+	}
+}
@@ -0,0 +1,564 @@
+// golang-analyzer analyzes functions, structures and calls of go source code.
+//
+// Usage:
+//
+//	golang-analyzer <command> [flags] [path]
+//
+// Commands are parse, functions, structures, interfaces, implementations, callers, callees, callgraph, sequence,
+// cfg, mermaid, json and diagnostics.
+// Path is a directory to analyze, and it is current directory by default.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"go/parser"
+	"io"
+	"io/fs"
+	"os"
+	"regexp"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/ariyn/golang-analyzer/analyzer"
+)
+
+const usage = `usage: golang-analyzer <command> [flags] [path]
+
+commands:
+  parse       analyze path and write snapshot into -o
+  functions   list functions
+  structures  list structures
+  interfaces  list interfaces
+  implementations
+              list types that implement -iface, or interfaces that -type implements
+  callers     list callers of -func
+  callees     list callees of -func
+  callgraph   print call graph as mermaid flowchart or graphviz dot
+  sequence    print mermaid sequence diagram of calls in -func
+  cfg         print control-flow graph of -func as mermaid flowchart or graphviz dot
+  mermaid     print mermaid class diagram of structures and interfaces
+  json        print snapshot as json
+  diagnostics print syntax that the analyzer can not handle
+
+run "golang-analyzer <command> -h" for flags of the command.
+`
+
+var errUsage = errors.New("invalid usage")
+
+type options struct {
+	recursive  bool
+	parallel   int
+	lenient    bool
+	dynamic    bool
+	typed      bool
+	comments   bool
+	tests      bool
+	exclude    string
+	timeout    time.Duration
+	progress   bool
+	snapshot   string
+	function   string
+	iface      string
+	typ        string
+	output     string
+	json       bool
+	severity   string
+	packages   string
+	depth      int
+	group      string
+	format     string
+	stdlib     bool
+	unresolved bool
+}
+
+func main() {
+	if err := run(os.Args[1:], os.Stdout, os.Stderr); err != nil {
+		if !errors.Is(err, errUsage) && !errors.Is(err, flag.ErrHelp) {
+			fmt.Fprintln(os.Stderr, err)
+		}
+		os.Exit(1)
+	}
+}
+
+func run(args []string, stdout, stderr io.Writer) (err error) {
+	if len(args) == 0 {
+		fmt.Fprint(stderr, usage)
+		return errUsage
+	}
+
+	command := args[0]
+	opts, path, err := parseFlags(command, args[1:], stderr)
+	if err != nil {
+		return
+	}
+
+	switch command {
+	case "parse":
+		return runParse(opts, path, stdout, stderr)
+	case "functions":
+		return runFunctions(opts, path, stdout, stderr)
+	case "structures":
+		return runStructures(opts, path, stdout, stderr)
+	case "interfaces":
+		return runInterfaces(opts, path, stdout, stderr)
+	case "implementations":
+		return runImplementations(opts, path, stdout, stderr)
+	case "callers":
+		return runCallers(opts, path, stdout, stderr)
+	case "callees":
+		return runCallees(opts, path, stdout, stderr)
+	case "callgraph":
+		return runCallGraph(opts, path, stdout, stderr)
+	case "sequence":
+		return runSequence(opts, path, stdout, stderr)
+	case "cfg":
+		return runCFG(opts, path, stdout, stderr)
+	case "mermaid":
+		return runMermaid(opts, path, stdout, stderr)
+	case "json":
+		return runJSON(opts, path, stdout, stderr)
+	case "diagnostics":
+		return runDiagnostics(opts, path, stdout, stderr)
+	}
+
+	fmt.Fprintf(stderr, "unknown command %q\n\n%s", command, usage)
+	return errUsage
+}
+
+func parseFlags(command string, args []string, stderr io.Writer) (opts options, path string, err error) {
+	flags := flag.NewFlagSet(command, flag.ContinueOnError)
+	flags.SetOutput(stderr)
+
+	flags.BoolVar(&opts.recursive, "recursive", false, "read go.mod and analyze every package of the module")
+	flags.IntVar(&opts.parallel, "parallel", runtime.NumCPU(), "number of packages to parse concurrently")
+	flags.BoolVar(&opts.lenient, "lenient", false, "skip files that have syntax error, instead of failing")
+	flags.BoolVar(&opts.dynamic, "dynamic", false, "link method calls on interfaces to every implementation, as possible calls")
+	flags.BoolVar(&opts.typed, "typed", false, "type-check packages with go/types to resolve calls exactly")
+	flags.BoolVar(&opts.comments, "comments", false, "parse comments")
+	flags.BoolVar(&opts.tests, "tests", true, "analyze _test.go files")
+	flags.StringVar(&opts.exclude, "exclude", "", "regular expression of file names to skip")
+	flags.DurationVar(&opts.timeout, "timeout", 0, "stop analyzing after the duration, like 30s. 0 means no limit")
+	flags.BoolVar(&opts.progress, "progress", false, "print progress of packages into stderr")
+
+	switch command {
+	case "parse":
+		flags.StringVar(&opts.output, "o", "", "file to write snapshot, stdout by default")
+	case "functions", "structures", "callers", "callees", "callgraph", "json":
+		flags.StringVar(&opts.snapshot, "snapshot", "", "read saved snapshot instead of analyzing path")
+	}
+
+	switch command {
+	case "callers", "callees":
+		flags.StringVar(&opts.function, "func", "", "identifier of function, like github.com/labstack/echo/v4.Echo.Start")
+	}
+
+	if command == "implementations" {
+		flags.StringVar(&opts.iface, "iface", "", "identifier of interface, like io.Reader")
+		flags.StringVar(&opts.typ, "type", "", "identifier of type, like *github.com/labstack/echo/v4.Echo")
+	}
+
+	if command == "callgraph" {
+		flags.StringVar(&opts.function, "func", "", "identifier of function to start from, every function by default")
+		flags.IntVar(&opts.depth, "depth", 0, "maximum number of calls from -func. 0 means no limit")
+		flags.StringVar(&opts.group, "group", "none", "group functions into subgraphs, one of none, package and receiver")
+		flags.StringVar(&opts.format, "format", "mermaid", "output format, one of mermaid and dot")
+		flags.BoolVar(&opts.stdlib, "stdlib", true, "draw functions of standard library and builtin functions")
+		flags.BoolVar(&opts.unresolved, "unresolved", true, "draw functions which are not declared in analyzed packages")
+	}
+
+	if command == "sequence" {
+		flags.StringVar(&opts.function, "func", "", "identifier of function, like github.com/labstack/echo/v4.Echo.ServeHTTP")
+		flags.IntVar(&opts.depth, "depth", 1, "levels of calls to follow into declared functions")
+	}
+
+	if command == "cfg" {
+		flags.StringVar(&opts.function, "func", "", "identifier of function, like github.com/labstack/echo/v4.Echo.ServeHTTP")
+		flags.StringVar(&opts.format, "format", "mermaid", "output format, one of mermaid and dot")
+	}
+
+	if command == "mermaid" {
+		flags.StringVar(&opts.packages, "packages", "", "comma separated import paths of packages to draw, every package by default")
+	}
+
+	if command == "diagnostics" {
+		flags.BoolVar(&opts.json, "json", false, "print diagnostics as json")
+		flags.StringVar(&opts.severity, "severity", "info", "minimum severity to print, one of info, warning and error")
+	}
+
+	if err = flags.Parse(args); err != nil {
+		return
+	}
+
+	switch flags.NArg() {
+	case 0:
+		path = "."
+	case 1:
+		path = flags.Arg(0)
+	default:
+		flags.Usage()
+		err = errUsage
+	}
+
+	return
+}
+
+func newParser(opts options, path string, stderr io.Writer) (p analyzer.Parser, err error) {
+	p = analyzer.NewParser(path)
+	p.SetRecursive(opts.recursive)
+	p.SetLenient(opts.lenient)
+	p.SetParallelism(opts.parallel)
+	p.SetDynamicDispatch(opts.dynamic)
+	p.SetTyped(opts.typed)
+
+	var mode parser.Mode
+	if opts.comments {
+		mode |= parser.ParseComments
+	}
+	p.SetMode(mode)
+
+	var exclude *regexp.Regexp
+	if opts.exclude != "" {
+		if exclude, err = regexp.Compile(opts.exclude); err != nil {
+			return
+		}
+	}
+
+	p.SetFilter(func(info fs.FileInfo) bool {
+		if !opts.tests && strings.HasSuffix(info.Name(), "_test.go") {
+			return false
+		}
+
+		return exclude == nil || !exclude.MatchString(info.Name())
+	})
+
+	if opts.progress {
+		p.SetProgress(func(progress analyzer.Progress) {
+			if progress.Kind == analyzer.PackageDone {
+				fmt.Fprintf(stderr, "[%d/%d] %s\n", progress.Packages, progress.TotalPackages, progress.Package)
+			}
+		})
+	}
+
+	ctx := context.Background()
+	if opts.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opts.timeout)
+		defer cancel()
+	}
+
+	if err = p.ParseContext(ctx); err != nil {
+		return
+	}
+
+	for _, fileErr := range p.Errors() {
+		fmt.Fprintln(stderr, fileErr)
+	}
+
+	return
+}
+
+// snapshot loads snapshot from -snapshot, or analyzes path.
+func snapshot(opts options, path string, stderr io.Writer) (s analyzer.Snapshot, err error) {
+	if opts.snapshot != "" {
+		var file *os.File
+		if file, err = os.Open(opts.snapshot); err != nil {
+			return
+		}
+		defer file.Close()
+
+		return analyzer.Load(file)
+	}
+
+	p, err := newParser(opts, path, stderr)
+	if err != nil {
+		return
+	}
+
+	return p.Snapshot(), nil
+}
+
+func runParse(opts options, path string, stdout, stderr io.Writer) (err error) {
+	p, err := newParser(opts, path, stderr)
+	if err != nil {
+		return
+	}
+
+	if opts.output == "" {
+		return p.Save(stdout)
+	}
+
+	file, err := os.Create(opts.output)
+	if err != nil {
+		return
+	}
+
+	if err = p.Save(file); err != nil {
+		file.Close()
+		return
+	}
+
+	return file.Close()
+}
+
+func runFunctions(opts options, path string, stdout, stderr io.Writer) (err error) {
+	s, err := snapshot(opts, path, stderr)
+	if err != nil {
+		return
+	}
+
+	for _, f := range s.Functions {
+		fmt.Fprintf(stdout, "%s\t%s\t%s:%d\n", f.Identifier, f.Signature, f.Pos.Filename, f.Pos.Line)
+	}
+
+	return
+}
+
+func runStructures(opts options, path string, stdout, stderr io.Writer) (err error) {
+	s, err := snapshot(opts, path, stderr)
+	if err != nil {
+		return
+	}
+
+	for _, strct := range s.Structures {
+		fmt.Fprintf(stdout, "%s\t%s:%d\n", strct.Identifier, strct.Pos.Filename, strct.Pos.Line)
+		for _, field := range strct.Fields {
+			fmt.Fprintf(stdout, "\tfield\t%s\n", field)
+		}
+		for _, method := range strct.Methods {
+			fmt.Fprintf(stdout, "\tmethod\t%s\n", method)
+		}
+	}
+
+	return
+}
+
+func runInterfaces(opts options, path string, stdout, stderr io.Writer) (err error) {
+	p, err := newParser(opts, path, stderr)
+	if err != nil {
+		return
+	}
+
+	for _, i := range p.Interfaces() {
+		fileName, line := p.LineInfo(i.Pos)
+		fmt.Fprintf(stdout, "%s\t%s:%d\n", i.Identifier(), fileName, line)
+		for _, embedded := range i.Embedded {
+			fmt.Fprintf(stdout, "\tembedded\t%s\n", embedded)
+		}
+		for _, method := range i.Methods {
+			fmt.Fprintf(stdout, "\tmethod\t%s\n", method)
+		}
+	}
+
+	return
+}
+
+func runImplementations(opts options, path string, stdout, stderr io.Writer) (err error) {
+	if (opts.iface == "") == (opts.typ == "") {
+		return errors.New("one of -iface and -type is required")
+	}
+
+	p, err := newParser(opts, path, stderr)
+	if err != nil {
+		return
+	}
+
+	var implementations []analyzer.Implementation
+	if opts.iface != "" {
+		implementations = p.Implementations(opts.iface)
+	} else {
+		implementations = p.Implements(opts.typ)
+	}
+
+	for _, implementation := range implementations {
+		fmt.Fprintln(stdout, implementation)
+	}
+
+	return
+}
+
+func runCallers(opts options, path string, stdout, stderr io.Writer) (err error) {
+	return printCalls(opts, path, stdout, stderr, func(call analyzer.CallSnapshot) (string, bool) {
+		return call.Caller, call.Callee == opts.function
+	})
+}
+
+func runCallees(opts options, path string, stdout, stderr io.Writer) (err error) {
+	return printCalls(opts, path, stdout, stderr, func(call analyzer.CallSnapshot) (string, bool) {
+		return call.Callee, call.Caller == opts.function
+	})
+}
+
+// printCalls prints calls that match returns true. name is the function to print for the call.
+func printCalls(opts options, path string, stdout, stderr io.Writer, match func(call analyzer.CallSnapshot) (name string, ok bool)) (err error) {
+	if opts.function == "" {
+		return errors.New("-func is required")
+	}
+
+	s, err := snapshot(opts, path, stderr)
+	if err != nil {
+		return
+	}
+
+	if _, ok := s.Function(opts.function); !ok {
+		return fmt.Errorf("function %q is not found", opts.function)
+	}
+
+	for _, call := range s.Calls {
+		if name, ok := match(call); ok {
+			possible := ""
+			if call.Possible {
+				possible = "\tpossible"
+			}
+
+			fmt.Fprintf(stdout, "%s\t%s:%d%s\n", name, call.Pos.Filename, call.Pos.Line, possible)
+		}
+	}
+
+	return
+}
+
+func runCallGraph(opts options, path string, stdout, stderr io.Writer) (err error) {
+	groups := map[string]analyzer.GroupBy{
+		"none":     analyzer.NoGroup,
+		"package":  analyzer.GroupByPackage,
+		"receiver": analyzer.GroupByReceiver,
+	}
+
+	group, ok := groups[opts.group]
+	if !ok {
+		return fmt.Errorf("unknown group %q", opts.group)
+	}
+
+	if opts.format != "mermaid" && opts.format != "dot" {
+		return fmt.Errorf("unknown format %q", opts.format)
+	}
+
+	s, err := snapshot(opts, path, stderr)
+	if err != nil {
+		return
+	}
+
+	g, err := s.CallGraph(analyzer.CallGraphOptions{
+		Root:         opts.function,
+		Depth:        opts.depth,
+		Group:        group,
+		NoStdlib:     !opts.stdlib,
+		NoUnresolved: !opts.unresolved,
+	})
+	if err != nil {
+		return
+	}
+
+	if opts.format == "dot" {
+		fmt.Fprint(stdout, g.DOT())
+	} else {
+		fmt.Fprint(stdout, g.Mermaid())
+	}
+
+	return
+}
+
+func runSequence(opts options, path string, stdout, stderr io.Writer) (err error) {
+	if opts.function == "" {
+		return errors.New("-func is required")
+	}
+
+	p, err := newParser(opts, path, stderr)
+	if err != nil {
+		return
+	}
+
+	f, ok := p.Function(opts.function)
+	if !ok {
+		return fmt.Errorf("function %q is not found", opts.function)
+	}
+
+	fmt.Fprint(stdout, p.SequenceDiagram(f, opts.depth))
+
+	return
+}
+
+func runCFG(opts options, path string, stdout, stderr io.Writer) (err error) {
+	if opts.function == "" {
+		return errors.New("-func is required")
+	}
+
+	if opts.format != "mermaid" && opts.format != "dot" {
+		return fmt.Errorf("unknown format %q", opts.format)
+	}
+
+	p, err := newParser(opts, path, stderr)
+	if err != nil {
+		return
+	}
+
+	f, ok := p.Function(opts.function)
+	if !ok {
+		return fmt.Errorf("function %q is not found", opts.function)
+	}
+
+	c := p.CFG(f)
+	if c == nil {
+		return fmt.Errorf("function %q has no body", opts.function)
+	}
+
+	if opts.format == "dot" {
+		fmt.Fprint(stdout, c.DOT())
+	} else {
+		fmt.Fprint(stdout, c.Mermaid())
+	}
+
+	return
+}
+
+func runMermaid(opts options, path string, stdout, stderr io.Writer) (err error) {
+	p, err := newParser(opts, path, stderr)
+	if err != nil {
+		return
+	}
+
+	var pkgs []string
+	if opts.packages != "" {
+		pkgs = strings.Split(opts.packages, ",")
+	}
+
+	fmt.Fprint(stdout, p.ClassDiagram(pkgs...))
+
+	return
+}
+
+func runJSON(opts options, path string, stdout, stderr io.Writer) (err error) {
+	s, err := snapshot(opts, path, stderr)
+	if err != nil {
+		return
+	}
+
+	return s.Save(stdout)
+}
+
+func runDiagnostics(opts options, path string, stdout, stderr io.Writer) (err error) {
+	var severity analyzer.Severity
+	if err = severity.UnmarshalText([]byte(opts.severity)); err != nil {
+		return
+	}
+
+	p, err := newParser(opts, path, io.Discard)
+	if err != nil {
+		return
+	}
+
+	diagnostics := p.Diagnostics().AtLeast(severity)
+	if opts.json {
+		return diagnostics.WriteJSON(stdout)
+	}
+
+	for _, d := range diagnostics {
+		fmt.Fprintln(stdout, d)
+	}
+
+	return
+}
@@ -0,0 +1,219 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func writeSampleModule(t *testing.T) (root string) {
+	root = t.TempDir()
+
+	files := map[string]string{
+		"go.mod": "module example.com/sample\n",
+		"main.go": `package main
+
+import "example.com/sample/util"
+
+func main() {
+	util.Run()
+}
+
+func start(r util.Runner) {
+	r.Run()
+}
+`,
+		"util/util.go": `package util
+
+type Server struct {
+	Name string
+}
+
+type Runner interface {
+	Run() error
+}
+
+func (s *Server) Run() error {
+	return nil
+}
+
+func Run() {}
+`,
+		"util/util_test.go": `package util
+
+func helper() {}
+`,
+	}
+
+	for name, source := range files {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := os.WriteFile(path, []byte(source), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return
+}
+
+func TestRun(t *testing.T) {
+	root := writeSampleModule(t)
+	snapshotPath := filepath.Join(t.TempDir(), "snapshot.json")
+
+	tests := []struct {
+		name     string
+		args     []string
+		contains []string
+		excludes []string
+		hasErr   bool
+	}{{
+		name:   "명령어가 없는 경우",
+		args:   []string{},
+		hasErr: true,
+	}, {
+		name:   "알 수 없는 명령어인 경우",
+		args:   []string{"unknown"},
+		hasErr: true,
+	}, {
+		name:     "함수 목록을 출력하는 경우",
+		args:     []string{"functions", "-recursive", root},
+		contains: []string{"example.com/sample.main", "example.com/sample/util.Run", "example.com/sample/util.helper"},
+	}, {
+		name:     "테스트 파일을 제외하는 경우",
+		args:     []string{"functions", "-recursive", "-tests=false", root},
+		contains: []string{"example.com/sample/util.Run"},
+		excludes: []string{"helper"},
+	}, {
+		name:     "구조체 목록을 출력하는 경우",
+		args:     []string{"structures", "-recursive", root},
+		contains: []string{"example.com/sample/util.Server", "field\tName string"},
+	}, {
+		name:     "인터페이스 목록을 출력하는 경우",
+		args:     []string{"interfaces", "-recursive", root},
+		contains: []string{"example.com/sample/util.Runner", "method\tRun()(error)"},
+	}, {
+		name:     "인터페이스를 구현하는 타입을 출력하는 경우",
+		args:     []string{"implementations", "-recursive", "-iface", "example.com/sample/util.Runner", root},
+		contains: []string{"*example.com/sample/util.Server implements example.com/sample/util.Runner"},
+	}, {
+		name:     "타입이 구현하는 인터페이스를 출력하는 경우",
+		args:     []string{"implementations", "-recursive", "-type", "*example.com/sample/util.Server", root},
+		contains: []string{"example.com/sample/util.Runner"},
+	}, {
+		name:   "인터페이스와 타입이 없는 경우",
+		args:   []string{"implementations", root},
+		hasErr: true,
+	}, {
+		name:     "호출자를 출력하는 경우",
+		args:     []string{"callers", "-recursive", "-func", "example.com/sample/util.Run", root},
+		contains: []string{"example.com/sample.main\t"},
+	}, {
+		name:     "피호출자를 출력하는 경우",
+		args:     []string{"callees", "-recursive", "-func", "example.com/sample.main", root},
+		contains: []string{"example.com/sample/util.Run\t"},
+	}, {
+		name:     "인터페이스를 통한 호출을 출력하는 경우",
+		args:     []string{"callees", "-recursive", "-dynamic", "-func", "example.com/sample.start", root},
+		contains: []string{"example.com/sample/util.Server.Run\t", "\tpossible"},
+	}, {
+		name:     "타입 검사로 피호출자를 출력하는 경우",
+		args:     []string{"callees", "-recursive", "-typed", "-func", "example.com/sample.main", root},
+		contains: []string{"example.com/sample/util.Run\t"},
+	}, {
+		name:   "함수를 찾을 수 없는 경우",
+		args:   []string{"callers", "-recursive", "-func", "example.com/sample.unknown", root},
+		hasErr: true,
+	}, {
+		name:     "호출 그래프를 mermaid로 출력하는 경우",
+		args:     []string{"callgraph", "-recursive", "-func", "example.com/sample.main", "-group", "package", root},
+		contains: []string{"flowchart LR", "subgraph group_example_com_sample_util", "example_com_sample_main --> example_com_sample_util_Run"},
+	}, {
+		name:     "호출 그래프를 dot으로 출력하는 경우",
+		args:     []string{"callgraph", "-recursive", "-format", "dot", "-unresolved=false", root},
+		contains: []string{"digraph calls {", `"example.com/sample.main" -> "example.com/sample/util.Run";`},
+	}, {
+		name:   "알 수 없는 그룹인 경우",
+		args:   []string{"callgraph", "-group", "file", root},
+		hasErr: true,
+	}, {
+		name:     "시퀀스 다이어그램을 출력하는 경우",
+		args:     []string{"sequence", "-recursive", "-func", "example.com/sample.start", root},
+		contains: []string{"sequenceDiagram", "participant example_com_sample_util_Runner as Runner", "example_com_sample->>example_com_sample_util_Runner: Run()"},
+	}, {
+		name:   "시퀀스 다이어그램의 함수를 찾을 수 없는 경우",
+		args:   []string{"sequence", "-recursive", "-func", "example.com/sample.unknown", root},
+		hasErr: true,
+	}, {
+		name:     "제어 흐름 그래프를 mermaid로 출력하는 경우",
+		args:     []string{"cfg", "-recursive", "-func", "example.com/sample.start", root},
+		contains: []string{"flowchart TD", `b0(["0: entry<br/>r.Run()"])`, "b0 --> b1"},
+	}, {
+		name:     "제어 흐름 그래프를 dot으로 출력하는 경우",
+		args:     []string{"cfg", "-recursive", "-func", "example.com/sample.start", "-format", "dot", root},
+		contains: []string{`digraph "example.com/sample.start" {`, "b0 -> b1;"},
+	}, {
+		name:   "제어 흐름 그래프의 함수가 없는 경우",
+		args:   []string{"cfg", "-recursive", root},
+		hasErr: true,
+	}, {
+		name:     "mermaid 다이어그램을 출력하는 경우",
+		args:     []string{"mermaid", "-recursive", root},
+		contains: []string{"classDiagram", "class Server", "+Run() error", "Runner <|.. Server"},
+	}, {
+		name:     "패키지를 지정해 mermaid 다이어그램을 출력하는 경우",
+		args:     []string{"mermaid", "-recursive", "-packages", "example.com/sample", root},
+		contains: []string{"classDiagram"},
+		excludes: []string{"class Server"},
+	}, {
+		name:     "진단 결과를 출력하는 경우",
+		args:     []string{"diagnostics", "-recursive", "-json", root},
+		contains: []string{"["},
+	}, {
+		name:   "알 수 없는 심각도인 경우",
+		args:   []string{"diagnostics", "-severity", "fatal", root},
+		hasErr: true,
+	}, {
+		name:   "시간이 초과된 경우",
+		args:   []string{"functions", "-recursive", "-timeout", "1ns", root},
+		hasErr: true,
+	}, {
+		name:     "진행 상황을 출력하는 경우",
+		args:     []string{"functions", "-recursive", "-progress", root},
+		contains: []string{"example.com/sample/util.Run"},
+	}, {
+		name: "스냅샷을 저장하는 경우",
+		args: []string{"parse", "-recursive", "-o", snapshotPath, root},
+	}, {
+		name:     "저장된 스냅샷을 읽는 경우",
+		args:     []string{"functions", "-snapshot", snapshotPath},
+		contains: []string{"example.com/sample/util.Run"},
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+
+			err := run(tt.args, &stdout, &stderr)
+			if tt.hasErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+
+			for _, s := range tt.contains {
+				assert.Contains(t, stdout.String(), s)
+			}
+
+			for _, s := range tt.excludes {
+				assert.False(t, strings.Contains(stdout.String(), s), s)
+			}
+		})
+	}
+}